@@ -0,0 +1,93 @@
+// Package client is a Go client for go-secrets that encrypts secret values locally before they are sent to the
+// server, so the server only ever stores opaque blobs.
+//
+// Keys are derived from the token and a passphrase. The server sees the token on every request, so the passphrase,
+// which never leaves the client, is what keeps the key out of its reach; it is required.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-secrets/models"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client stores and reads client encrypted secrets.
+type Client struct {
+	BaseURL    string
+	Token      string
+	Passphrase []byte
+	HTTPClient *http.Client
+}
+
+// New creates a client for the server at baseURL that authenticates with the token and encrypts values with a key
+// derived from the token and the passphrase, which must not be empty.
+func New(baseURL string, token string, passphrase []byte) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Token:      token,
+		Passphrase: passphrase,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// PutSecret encrypts the value locally and stores it at the secret key.
+func (c *Client) PutSecret(ctx context.Context, key string, value []byte) error {
+	blob, err := Encrypt(c.Token, c.Passphrase, key, value)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(models.StoreSecretRequest{Value: blob, ClientEncrypted: true})
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, key, body, nil)
+}
+
+// GetSecret reads the secret key and decrypts its value locally.
+func (c *Client) GetSecret(ctx context.Context, key string) ([]byte, error) {
+	var resp models.GetSecretResponse
+	if err := c.do(ctx, http.MethodGet, key, nil, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.ClientEncrypted {
+		return nil, fmt.Errorf("secret %s is not client encrypted", key)
+	}
+	return Decrypt(c.Token, c.Passphrase, key, resp.Value)
+}
+
+// do sends a request for the secret key and decodes the response into out, if given.
+func (c *Client) do(ctx context.Context, method string, key string, body []byte, out any) error {
+	endpoint := c.BaseURL + "/secret/" + (&url.URL{Path: strings.TrimPrefix(key, "/")}).EscapedPath()
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp models.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Message == "" {
+			return fmt.Errorf("go-secrets: unexpected status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("go-secrets: %s (status %d)", errResp.Message, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
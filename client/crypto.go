@@ -0,0 +1,119 @@
+package client
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// blobVersion is the first byte of every blob, laid out as version | salt | nonce | AES-256-GCM ciphertext.
+	blobVersion = 1
+	saltSize    = 16
+	keySize     = 32
+	// keyPurpose binds the derived keys to client-side secret encryption.
+	keyPurpose = "go-secrets client encryption"
+)
+
+var (
+	ErrInvalidBlob       = errors.New("invalid client encrypted blob")
+	ErrDecryption        = errors.New("failed to decrypt client encrypted blob, wrong token, passphrase or key")
+	ErrMissingPassphrase = errors.New("client encryption requires a passphrase")
+)
+
+// Encrypt encrypts the value on the client with a key derived from the token and passphrase, bound to the secret key.
+// The returned blob is opaque to the server. The passphrase must not be empty, since the server could derive the key
+// from the token alone.
+func Encrypt(token string, passphrase []byte, key string, value []byte) (string, error) {
+	if len(passphrase) == 0 {
+		return "", ErrMissingPassphrase
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(deriveSecret(token, passphrase), salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	blob := make([]byte, 0, 1+saltSize+len(nonce)+len(value)+aead.Overhead())
+	blob = append(blob, blobVersion)
+	blob = append(blob, salt...)
+	blob = append(blob, nonce...)
+	blob = aead.Seal(blob, nonce, value, associatedData(key))
+	return base64.StdEncoding.EncodeToString(blob), nil
+}
+
+// Decrypt decrypts a blob produced by Encrypt for the same token, passphrase and secret key.
+func Decrypt(token string, passphrase []byte, key string, encoded string) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrMissingPassphrase
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(blob) < 1+saltSize || blob[0] != blobVersion {
+		return nil, ErrInvalidBlob
+	}
+	salt := blob[1 : 1+saltSize]
+
+	aead, err := newAEAD(deriveSecret(token, passphrase), salt)
+	if err != nil {
+		return nil, err
+	}
+
+	data := blob[1+saltSize:]
+	if len(data) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidBlob
+	}
+
+	value, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], associatedData(key))
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return value, nil
+}
+
+// deriveSecret returns the input keying material for a token and passphrase. Both are length-prefixed, so no two
+// different pairs share it.
+func deriveSecret(token string, passphrase []byte) []byte {
+	secret := make([]byte, 0, 8+len(token)+len(passphrase))
+	secret = binary.BigEndian.AppendUint32(secret, uint32(len(token)))
+	secret = append(secret, token...)
+	secret = binary.BigEndian.AppendUint32(secret, uint32(len(passphrase)))
+	secret = append(secret, passphrase...)
+	return secret
+}
+
+// newAEAD derives an AES-256-GCM key from the secret with HKDF-SHA256.
+func newAEAD(secret []byte, salt []byte) (cipher.AEAD, error) {
+	derived := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(keyPurpose)), derived); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// associatedData binds a blob to its secret key, so it cannot be moved to another key unnoticed.
+func associatedData(key string) []byte {
+	return []byte(keyPurpose + "\x00" + key)
+}
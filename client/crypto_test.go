@@ -0,0 +1,63 @@
+package client
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Run("round trips value", func(t *testing.T) {
+		blob, err := Encrypt("token", []byte("passphrase"), "db/password", []byte("hunter2"))
+		assert.NoError(t, err)
+
+		value, err := Decrypt("token", []byte("passphrase"), "db/password", blob)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hunter2"), value)
+	})
+
+	t.Run("fails with wrong token, passphrase or secret key", func(t *testing.T) {
+		blob, err := Encrypt("token", []byte("passphrase"), "db/password", []byte("hunter2"))
+		assert.NoError(t, err)
+
+		_, err = Decrypt("other", []byte("passphrase"), "db/password", blob)
+		assert.ErrorIs(t, err, ErrDecryption)
+		_, err = Decrypt("token", []byte("other"), "db/password", blob)
+		assert.ErrorIs(t, err, ErrDecryption)
+		_, err = Decrypt("token", []byte("passphrase"), "db/other", blob)
+		assert.ErrorIs(t, err, ErrDecryption)
+	})
+
+	t.Run("does not confuse token and passphrase boundaries", func(t *testing.T) {
+		blob, err := Encrypt("ab", []byte("c"), "db/password", []byte("hunter2"))
+		assert.NoError(t, err)
+
+		_, err = Decrypt("a", []byte("bc"), "db/password", blob)
+		assert.ErrorIs(t, err, ErrDecryption)
+	})
+
+	t.Run("requires a passphrase", func(t *testing.T) {
+		_, err := Encrypt("token", nil, "db/password", []byte("hunter2"))
+		assert.ErrorIs(t, err, ErrMissingPassphrase)
+		_, err = Decrypt("token", []byte{}, "db/password", "AQ==")
+		assert.ErrorIs(t, err, ErrMissingPassphrase)
+	})
+
+	t.Run("rejects malformed blob", func(t *testing.T) {
+		_, err := Decrypt("token", []byte("passphrase"), "db/password", "not base64!")
+		assert.ErrorIs(t, err, ErrInvalidBlob)
+		_, err = Decrypt("token", []byte("passphrase"), "db/password", "AQ==")
+		assert.ErrorIs(t, err, ErrInvalidBlob)
+
+		blob, err := Encrypt("token", []byte("passphrase"), "db/password", []byte("hunter2"))
+		require.NoError(t, err)
+		data, err := base64.StdEncoding.DecodeString(blob)
+		require.NoError(t, err)
+		data[0] = blobVersion + 1
+		_, err = Decrypt("token", []byte("passphrase"), "db/password", base64.StdEncoding.EncodeToString(data))
+		assert.ErrorIs(t, err, ErrInvalidBlob)
+	})
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get the server configuration
+// @Description Returns the effective server configuration. Requires a root token. The configuration is read-only, it is set by environment variables at startup.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ServerConfigResponse "Server configuration"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Router /admin/config [get]
+func (ac *AdminControllerImpl) Config(ctx *gin.Context) {
+	response := ac.ServerConfig
+	response.SealType = string(ac.Seal.Status().Type)
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"go-secrets/internal"
+	"go-secrets/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController interface {
+	Stats(ctx *gin.Context)
+	Config(ctx *gin.Context)
+}
+
+type AdminControllerImpl struct {
+	Logger       internal.LoggerService
+	Seal         internal.SealService
+	StoreStats   internal.StatsService
+	ServerConfig models.ServerConfigResponse
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"go-secrets/errors"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get store statistics
+// @Description Counts the tokens, secrets, policies, AppRoles and keys in the store. Requires a root token.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.StoreStatsResponse "Store statistics"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /admin/stats [get]
+func (ac *AdminControllerImpl) Stats(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	stats, err := ac.StoreStats.Collect(requestCtx)
+	if err != nil {
+		ac.Logger.LogError(requestCtx, "failed to collect store statistics", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	response := models.StoreStatsResponse{
+		Keys:             stats.Keys,
+		Tokens:           stats.Tokens,
+		Secrets:          stats.Secrets,
+		BlobChunks:       stats.BlobChunks,
+		Policies:         stats.Policies,
+		PasswordPolicies: stats.PasswordPolicies,
+		TransitKeys:      stats.TransitKeys,
+		SigningKeys:      stats.SigningKeys,
+		AppRoles:         stats.AppRoles,
+		KeyVersion:       ac.Seal.Status().KeyVersion,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
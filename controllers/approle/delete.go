@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Delete an AppRole
+// @Description Deletes a named AppRole and all secret IDs issued for it. Tokens issued through it stay valid until they expire or are revoked. Requires the root token.
+// @Tags sys
+// @Param name path string true "AppRole name"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "AppRole not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/approle/roles/{name} [delete]
+func (ac *AppRoleControllerImpl) Delete(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	err := ac.AppRoles.DeleteRole(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrAppRoleNotFound) {
+		ac.Logger.LogWarn(requestCtx, "approle not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		ac.Logger.LogError(requestCtx, "failed to delete approle", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
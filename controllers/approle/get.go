@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get an AppRole
+// @Description Returns a named AppRole and its role ID. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Param name path string true "AppRole name"
+// @Security BearerAuth
+// @Success 200 {object} models.AppRoleResponse "AppRole"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "AppRole not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/approle/roles/{name} [get]
+func (ac *AppRoleControllerImpl) Get(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	role, err := ac.AppRoles.GetRole(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrAppRoleNotFound) {
+		ac.Logger.LogWarn(requestCtx, "approle not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		ac.Logger.LogError(requestCtx, "failed to get approle", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roleResponse(role))
+}
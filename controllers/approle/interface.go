@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"go-secrets/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AppRoleController interface {
+	Put(ctx *gin.Context)
+	Get(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	IssueSecretID(ctx *gin.Context)
+	Login(ctx *gin.Context)
+}
+
+type AppRoleControllerImpl struct {
+	Logger   internal.LoggerService
+	Redis    internal.RedisService
+	Token    internal.TokenService
+	AppRoles internal.AppRoleService
+	Policies internal.PolicyService
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Log in with an AppRole
+// @Description Exchanges a role ID and secret ID for a token with the AppRole's policies, TTL, max TTL and use limit.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body models.AppRoleLoginRequest true "Role ID and secret ID"
+// @Success 200 {object} models.IssueTokenResponse "Issued token"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Invalid role ID or secret ID, or not allowed from this address"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/approle/login [post]
+func (ac *AppRoleControllerImpl) Login(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var request models.AppRoleLoginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ac.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	// Check CIDRs against the address of the connection, which a client cannot choose the way it can proxy headers
+	role, err := ac.AppRoles.Login(requestCtx, request.RoleID, request.SecretID, ctx.RemoteIP())
+	if stderrors.Is(err, internal.ErrInvalidAppRoleLogin) {
+		ac.Logger.LogWarn(requestCtx, "approle login failed", requestID, err)
+		errors.ErrInvalidAppRoleLogin.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		ac.Logger.LogError(requestCtx, "failed to log in with approle", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	token, err := ac.Token.GenerateToken()
+	if err != nil {
+		ac.Logger.LogError(requestCtx, "failed to generate token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	record := internal.TokenRecord{
+		DisplayName: "approle-" + role.Name,
+		Labels:      map[string]string{"approle": role.Name},
+		Policies:    role.Policies,
+		CreatedAt:   time.Now(),
+		CreatorIP:   ctx.ClientIP(),
+		TTL:         role.TokenTTL,
+		MaxTTL:      role.TokenMaxTTL,
+		NumUses:     role.TokenNumUses,
+	}
+	stored, err := ac.Token.StoreToken(requestCtx, token, time.Duration(role.TokenTTL)*time.Second, record, ac.Redis)
+	if err != nil {
+		ac.Logger.LogError(requestCtx, "failed to store token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	// Record the issued token's accessor in the request log
+	ctx.Set("token_accessor", stored.Accessor)
+
+	response := models.IssueTokenResponse{
+		Token:    token,
+		Accessor: stored.Accessor,
+		TTL:      role.TokenTTL,
+		MaxTTL:   role.TokenMaxTTL,
+		NumUses:  role.TokenNumUses,
+		Policies: role.Policies,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
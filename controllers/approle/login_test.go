@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"go-secrets/helpers"
+	"go-secrets/internal"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// cidrAppRoles accepts logins only from addresses in its CIDRs and records the address of the last login.
+type cidrAppRoles struct {
+	internal.AppRoleService
+	cidrs []string
+	ip    string
+}
+
+func (a *cidrAppRoles) Login(ctx context.Context, roleID string, secretID string, ip string) (*internal.AppRole, error) {
+	a.ip = ip
+	if !helpers.CIDRsContain(a.cidrs, ip) {
+		return nil, fmt.Errorf("%w: %s is not allowed", internal.ErrInvalidAppRoleLogin, ip)
+	}
+	return &internal.AppRole{Name: "ci"}, nil
+}
+
+func TestLoginIgnoresForwardedAddresses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	appRoles := &cidrAppRoles{cidrs: []string{"10.0.0.0/8"}}
+	controller := &AppRoleControllerImpl{Logger: internal.NewLogger(slog.LevelError, io.Discard), AppRoles: appRoles}
+
+	// A router trusting every proxy, so the controller alone must not rely on forwarded headers
+	router := gin.New()
+	router.POST("/auth/approle/login", controller.Login)
+
+	request := httptest.NewRequest(http.MethodPost, "/auth/approle/login", strings.NewReader(`{"role_id":"r","secret_id":"s"}`))
+	request.RemoteAddr = "203.0.113.7:40000"
+	request.Header.Set("X-Forwarded-For", "10.0.0.5")
+	request.Header.Set("X-Real-IP", "10.0.0.5")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "203.0.113.7", appRoles.ip)
+}
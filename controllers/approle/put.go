@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	stderrors "errors"
+	tokenControllers "go-secrets/controllers/token"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create or update an AppRole
+// @Description Stores a named AppRole that machines log in with to get tokens with its policies, TTL and use limit. An existing role keeps its role ID and secret IDs. Requires the root token.
+// @Tags sys
+// @Accept json
+// @Produce json
+// @Param name path string true "AppRole name"
+// @Param body body models.AppRoleRequest true "AppRole"
+// @Security BearerAuth
+// @Success 200 {object} models.AppRoleResponse "Stored AppRole"
+// @Failure 400 {object} models.ErrorResponse "Invalid AppRole name, TTLs, use limits, CIDRs or unknown policy"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/approle/roles/{name} [put]
+func (ac *AppRoleControllerImpl) Put(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var request models.AppRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ac.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if request.TokenTTL == 0 {
+		request.TokenTTL = tokenControllers.DefaultTTL
+	}
+	if request.TokenMaxTTL == 0 {
+		request.TokenMaxTTL = max(tokenControllers.DefaultMaxTTL, request.TokenTTL)
+	}
+	if request.TokenTTL < 0 || request.TokenTTL > tokenControllers.MaxTTL ||
+		request.TokenMaxTTL < request.TokenTTL || request.TokenMaxTTL > tokenControllers.MaxRenewableTTL ||
+		request.TokenNumUses < 0 || request.SecretIDTTL < 0 || request.SecretIDNumUses < 0 {
+		ac.Logger.LogWarn(requestCtx, "invalid approle ttl or use limit", requestID, nil)
+		errors.ErrInvalidAppRole.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	for _, name := range request.Policies {
+		if _, err := ac.Policies.Get(requestCtx, name); stderrors.Is(err, internal.ErrPolicyNotFound) {
+			ac.Logger.LogWarn(requestCtx, "policy not found", requestID, err)
+			errors.ErrPolicyNotFound.WithRequestID(ctx).JSON(ctx)
+			return
+		} else if err != nil {
+			ac.Logger.LogError(requestCtx, "failed to get policy", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+
+	role, err := ac.AppRoles.PutRole(requestCtx, internal.AppRole{
+		Name:               ctx.Param("name"),
+		Policies:           request.Policies,
+		TokenTTL:           request.TokenTTL,
+		TokenMaxTTL:        request.TokenMaxTTL,
+		TokenNumUses:       request.TokenNumUses,
+		SecretIDTTL:        request.SecretIDTTL,
+		SecretIDNumUses:    request.SecretIDNumUses,
+		SecretIDBoundCIDRs: request.SecretIDBoundCIDRs,
+	})
+	if err != nil {
+		switch {
+		case stderrors.Is(err, internal.ErrInvalidKeyName):
+			ac.Logger.LogWarn(requestCtx, "invalid approle name", requestID, err)
+			errors.ErrInvalidKeyName.WithRequestID(ctx).JSON(ctx)
+		case stderrors.Is(err, internal.ErrInvalidAppRole):
+			ac.Logger.LogWarn(requestCtx, "invalid approle", requestID, err)
+			errors.ErrInvalidAppRole.WithRequestID(ctx).JSON(ctx)
+		default:
+			ac.Logger.LogError(requestCtx, "failed to store approle", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roleResponse(role))
+}
+
+// roleResponse builds the response describing an AppRole.
+func roleResponse(role *internal.AppRole) models.AppRoleResponse {
+	return models.AppRoleResponse{
+		Name:               role.Name,
+		RoleID:             role.RoleID,
+		Policies:           role.Policies,
+		TokenTTL:           role.TokenTTL,
+		TokenMaxTTL:        role.TokenMaxTTL,
+		TokenNumUses:       role.TokenNumUses,
+		SecretIDTTL:        role.SecretIDTTL,
+		SecretIDNumUses:    role.SecretIDNumUses,
+		SecretIDBoundCIDRs: role.SecretIDBoundCIDRs,
+		CreatedAt:          role.CreatedAt,
+	}
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Issue a secret ID
+// @Description Issues a secret ID for an AppRole, which is only returned once. Its TTL and use limit default to the role's and cannot exceed them, and its CIDRs restrict logins in addition to the role's. Requires the root token.
+// @Tags sys
+// @Accept json
+// @Produce json
+// @Param name path string true "AppRole name"
+// @Param body body models.IssueSecretIDRequest false "Secret ID restrictions"
+// @Security BearerAuth
+// @Success 200 {object} models.SecretIDResponse "Issued secret ID"
+// @Failure 400 {object} models.ErrorResponse "Invalid TTL, use limit or CIDRs"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "AppRole not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/approle/roles/{name}/secret-id [post]
+func (ac *AppRoleControllerImpl) IssueSecretID(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var request models.IssueSecretIDRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ac.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+			errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+
+	role, err := ac.AppRoles.GetRole(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrAppRoleNotFound) {
+		ac.Logger.LogWarn(requestCtx, "approle not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		ac.Logger.LogError(requestCtx, "failed to get approle", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if request.TTL == 0 {
+		request.TTL = role.SecretIDTTL
+	}
+	if request.NumUses == 0 {
+		request.NumUses = role.SecretIDNumUses
+	}
+	if request.TTL < 0 || (role.SecretIDTTL > 0 && request.TTL > role.SecretIDTTL) ||
+		request.NumUses < 0 || (role.SecretIDNumUses > 0 && request.NumUses > role.SecretIDNumUses) {
+		ac.Logger.LogWarn(requestCtx, "invalid secret id ttl or use limit", requestID, nil)
+		errors.ErrInvalidAppRole.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	secretID := internal.SecretID{
+		CIDRs:   request.CIDRList,
+		NumUses: request.NumUses,
+	}
+	value, err := ac.AppRoles.IssueSecretID(requestCtx, role.Name, time.Duration(request.TTL)*time.Second, secretID)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, internal.ErrAppRoleNotFound):
+			ac.Logger.LogWarn(requestCtx, "approle not found", requestID, err)
+			errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		case stderrors.Is(err, internal.ErrInvalidAppRole):
+			ac.Logger.LogWarn(requestCtx, "invalid secret id cidrs", requestID, err)
+			errors.ErrInvalidAppRole.WithRequestID(ctx).JSON(ctx)
+		default:
+			ac.Logger.LogError(requestCtx, "failed to issue secret id", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		}
+		return
+	}
+
+	response := models.SecretIDResponse{
+		SecretID: value,
+		TTL:      request.TTL,
+		NumUses:  request.NumUses,
+		CIDRList: request.CIDRList,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Delete a certificate role
+// @Description Deletes a certificate role. Tokens issued by logging in with it stay valid until they expire or are revoked, session tokens of client certificates stop working. Requires the root token.
+// @Tags sys
+// @Param name path string true "Role name"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "Certificate role not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/cert/roles/{name} [delete]
+func (cc *CertControllerImpl) DeleteRole(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	err := cc.CertAuth.DeleteRole(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrCertRoleNotFound) {
+		cc.Logger.LogWarn(requestCtx, "certificate role not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		cc.Logger.LogError(requestCtx, "failed to delete certificate role", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
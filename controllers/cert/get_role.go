@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a certificate role
+// @Description Returns a certificate role. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Param name path string true "Role name"
+// @Security BearerAuth
+// @Success 200 {object} models.CertRoleResponse "Certificate role"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "Certificate role not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/cert/roles/{name} [get]
+func (cc *CertControllerImpl) GetRole(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	role, err := cc.CertAuth.GetRole(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrCertRoleNotFound) {
+		cc.Logger.LogWarn(requestCtx, "certificate role not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		cc.Logger.LogError(requestCtx, "failed to get certificate role", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roleResponse(role))
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"go-secrets/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CertController interface {
+	PutRole(ctx *gin.Context)
+	GetRole(ctx *gin.Context)
+	DeleteRole(ctx *gin.Context)
+	Login(ctx *gin.Context)
+}
+
+type CertControllerImpl struct {
+	Logger   internal.LoggerService
+	Redis    internal.RedisService
+	Token    internal.TokenService
+	CertAuth internal.CertAuthService
+	Policies internal.PolicyService
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/helpers"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Log in with a client certificate
+// @Description Exchanges the client certificate of the TLS connection, verified against the configured CA bundle, for a token with the policies, TTL, max TTL and use limit of the named role, or of the first role by name the certificate matches.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body models.CertLoginRequest false "Role name"
+// @Success 200 {object} models.IssueTokenResponse "Issued token"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Missing client certificate or not allowed to log in with the role"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/cert/login [post]
+func (cc *CertControllerImpl) Login(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var request models.CertLoginRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			cc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+			errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+
+	cert := helpers.VerifiedClientCertificate(ctx.Request)
+	if cert == nil {
+		cc.Logger.LogWarn(requestCtx, "no verified client certificate", requestID, nil)
+		errors.ErrInvalidCertLogin.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	role, err := cc.CertAuth.Login(requestCtx, request.Role, cert)
+	if stderrors.Is(err, internal.ErrInvalidCertLogin) {
+		cc.Logger.LogWarn(requestCtx, "certificate login failed", requestID, err)
+		errors.ErrInvalidCertLogin.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		cc.Logger.LogError(requestCtx, "failed to log in with certificate", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	token, err := cc.Token.GenerateToken()
+	if err != nil {
+		cc.Logger.LogError(requestCtx, "failed to generate token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	record := internal.TokenRecord{
+		DisplayName: internal.CertDisplayName(cert),
+		Labels:      map[string]string{"cert_role": role.Name},
+		Policies:    role.Policies,
+		CreatedAt:   time.Now(),
+		CreatorIP:   ctx.ClientIP(),
+		TTL:         role.TokenTTL,
+		MaxTTL:      role.TokenMaxTTL,
+		NumUses:     role.TokenNumUses,
+	}
+	stored, err := cc.Token.StoreToken(requestCtx, token, time.Duration(role.TokenTTL)*time.Second, record, cc.Redis)
+	if err != nil {
+		cc.Logger.LogError(requestCtx, "failed to store token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	// Record the issued token's accessor in the request log
+	ctx.Set("token_accessor", stored.Accessor)
+
+	response := models.IssueTokenResponse{
+		Token:    token,
+		Accessor: stored.Accessor,
+		TTL:      role.TokenTTL,
+		MaxTTL:   role.TokenMaxTTL,
+		NumUses:  role.TokenNumUses,
+		Policies: role.Policies,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	stderrors "errors"
+	tokenControllers "go-secrets/controllers/token"
+	"go-secrets/errors"
+	"go-secrets/helpers"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create or update a certificate role
+// @Description Stores a named certificate role that maps verified client certificates matching its allowed common names, organizational units and SANs to tokens with its policies, TTL and use limit. Requires the root token.
+// @Tags sys
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param body body models.CertRoleRequest true "Certificate role"
+// @Security BearerAuth
+// @Success 200 {object} models.CertRoleResponse "Stored certificate role"
+// @Failure 400 {object} models.ErrorResponse "Invalid role name, TTLs, allowed names or unknown policy"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/cert/roles/{name} [put]
+func (cc *CertControllerImpl) PutRole(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var request models.CertRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		cc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if request.TokenTTL == 0 {
+		request.TokenTTL = tokenControllers.DefaultTTL
+	}
+	if request.TokenMaxTTL == 0 {
+		request.TokenMaxTTL = max(tokenControllers.DefaultMaxTTL, request.TokenTTL)
+	}
+	if request.TokenTTL < 0 || request.TokenTTL > tokenControllers.MaxTTL ||
+		request.TokenMaxTTL < request.TokenTTL || request.TokenMaxTTL > tokenControllers.MaxRenewableTTL ||
+		request.TokenNumUses < 0 {
+		cc.Logger.LogWarn(requestCtx, "invalid certificate role ttl or use limit", requestID, nil)
+		errors.ErrInvalidCertRole.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	for _, name := range request.Policies {
+		if _, err := cc.Policies.Get(requestCtx, name); stderrors.Is(err, internal.ErrPolicyNotFound) {
+			cc.Logger.LogWarn(requestCtx, "policy not found", requestID, err)
+			errors.ErrPolicyNotFound.WithRequestID(ctx).JSON(ctx)
+			return
+		} else if err != nil {
+			cc.Logger.LogError(requestCtx, "failed to get policy", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+
+	role, err := cc.CertAuth.PutRole(requestCtx, internal.CertRole{
+		Name: ctx.Param("name"),
+		CertificateConstraints: helpers.CertificateConstraints{
+			CommonNames:         request.AllowedCommonNames,
+			OrganizationalUnits: request.AllowedOrganizationalUnits,
+			DNSNames:            request.AllowedDNSSANs,
+			EmailAddresses:      request.AllowedEmailSANs,
+			URIs:                request.AllowedURISANs,
+		},
+		Policies:     request.Policies,
+		TokenTTL:     request.TokenTTL,
+		TokenMaxTTL:  request.TokenMaxTTL,
+		TokenNumUses: request.TokenNumUses,
+	})
+	if err != nil {
+		switch {
+		case stderrors.Is(err, internal.ErrInvalidKeyName):
+			cc.Logger.LogWarn(requestCtx, "invalid certificate role name", requestID, err)
+			errors.ErrInvalidKeyName.WithRequestID(ctx).JSON(ctx)
+		case stderrors.Is(err, internal.ErrInvalidCertRole):
+			cc.Logger.LogWarn(requestCtx, "invalid certificate role", requestID, err)
+			errors.ErrInvalidCertRole.WithRequestID(ctx).JSON(ctx)
+		default:
+			cc.Logger.LogError(requestCtx, "failed to store certificate role", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roleResponse(role))
+}
+
+// roleResponse builds the response describing a certificate role.
+func roleResponse(role *internal.CertRole) models.CertRoleResponse {
+	return models.CertRoleResponse{
+		Name:                       role.Name,
+		AllowedCommonNames:         role.CommonNames,
+		AllowedOrganizationalUnits: role.OrganizationalUnits,
+		AllowedDNSSANs:             role.DNSNames,
+		AllowedEmailSANs:           role.EmailAddresses,
+		AllowedURISANs:             role.URIs,
+		Policies:                   role.Policies,
+		TokenTTL:                   role.TokenTTL,
+		TokenMaxTTL:                role.TokenMaxTTL,
+		TokenNumUses:               role.TokenNumUses,
+	}
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Delete a JWT issuer
+// @Description Deletes a trusted JWT issuer. Roles referencing it can no longer be logged in with. Requires the root token.
+// @Tags sys
+// @Param name path string true "Issuer name"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "JWT issuer not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/jwt/issuers/{name} [delete]
+func (jc *JWTControllerImpl) DeleteIssuer(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	err := jc.JWTAuth.DeleteIssuer(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrJWTIssuerNotFound) {
+		jc.Logger.LogWarn(requestCtx, "jwt issuer not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		jc.Logger.LogError(requestCtx, "failed to delete jwt issuer", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Delete a JWT role
+// @Description Deletes a JWT role. Tokens issued through it stay valid until they expire or are revoked. Requires the root token.
+// @Tags sys
+// @Param name path string true "Role name"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "JWT role not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/jwt/roles/{name} [delete]
+func (jc *JWTControllerImpl) DeleteRole(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	err := jc.JWTAuth.DeleteRole(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrJWTRoleNotFound) {
+		jc.Logger.LogWarn(requestCtx, "jwt role not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		jc.Logger.LogError(requestCtx, "failed to delete jwt role", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a JWT issuer
+// @Description Returns a trusted JWT issuer. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Param name path string true "Issuer name"
+// @Security BearerAuth
+// @Success 200 {object} models.JWTIssuerResponse "JWT issuer"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "JWT issuer not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/jwt/issuers/{name} [get]
+func (jc *JWTControllerImpl) GetIssuer(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	issuer, err := jc.JWTAuth.GetIssuer(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrJWTIssuerNotFound) {
+		jc.Logger.LogWarn(requestCtx, "jwt issuer not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		jc.Logger.LogError(requestCtx, "failed to get jwt issuer", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, issuerResponse(issuer))
+}
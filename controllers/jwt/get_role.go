@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a JWT role
+// @Description Returns a JWT role. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Param name path string true "Role name"
+// @Security BearerAuth
+// @Success 200 {object} models.JWTRoleResponse "JWT role"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "JWT role not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/jwt/roles/{name} [get]
+func (jc *JWTControllerImpl) GetRole(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	role, err := jc.JWTAuth.GetRole(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrJWTRoleNotFound) {
+		jc.Logger.LogWarn(requestCtx, "jwt role not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		jc.Logger.LogError(requestCtx, "failed to get jwt role", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roleResponse(role))
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"go-secrets/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWTController interface {
+	PutIssuer(ctx *gin.Context)
+	GetIssuer(ctx *gin.Context)
+	DeleteIssuer(ctx *gin.Context)
+	PutRole(ctx *gin.Context)
+	GetRole(ctx *gin.Context)
+	DeleteRole(ctx *gin.Context)
+	Login(ctx *gin.Context)
+}
+
+type JWTControllerImpl struct {
+	Logger   internal.LoggerService
+	Redis    internal.RedisService
+	Token    internal.TokenService
+	JWTAuth  internal.JWTAuthService
+	Policies internal.PolicyService
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/helpers"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Log in with a JWT
+// @Description Exchanges a JWT signed by the role's issuer and matching the role's bound audiences, subject and claims for a token with the role's policies, TTL, max TTL and use limit. Mapped claims become token labels.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body models.JWTLoginRequest true "Role name and JWT"
+// @Success 200 {object} models.IssueTokenResponse "Issued token"
+// @Failure 400 {object} models.ErrorResponse "Invalid request or mapped claims that are not valid token metadata"
+// @Failure 401 {object} models.ErrorResponse "Invalid JWT or not allowed to log in with the role"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/jwt/login [post]
+func (jc *JWTControllerImpl) Login(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var request models.JWTLoginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		jc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	role, jwt, err := jc.JWTAuth.Login(requestCtx, request.Role, request.JWT)
+	if stderrors.Is(err, internal.ErrInvalidJWTLogin) {
+		jc.Logger.LogWarn(requestCtx, "jwt login failed", requestID, err)
+		errors.ErrInvalidJWTLogin.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		jc.Logger.LogError(requestCtx, "failed to log in with jwt", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	displayName, _ := jwt.ClaimString(role.UserClaim)
+	labels := map[string]string{"jwt_role": role.Name}
+	for claim, label := range role.ClaimMappings {
+		if value, ok := jwt.ClaimString(claim); ok {
+			labels[label] = value
+		}
+	}
+	if err := helpers.ValidateTokenMetadata(displayName, labels); err != nil {
+		jc.Logger.LogWarn(requestCtx, "invalid token metadata from jwt claims", requestID, err)
+		errors.ErrInvalidTokenMetadata.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	token, err := jc.Token.GenerateToken()
+	if err != nil {
+		jc.Logger.LogError(requestCtx, "failed to generate token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	record := internal.TokenRecord{
+		DisplayName: displayName,
+		Labels:      labels,
+		Policies:    role.Policies,
+		CreatedAt:   time.Now(),
+		CreatorIP:   ctx.ClientIP(),
+		TTL:         role.TokenTTL,
+		MaxTTL:      role.TokenMaxTTL,
+		NumUses:     role.TokenNumUses,
+	}
+	stored, err := jc.Token.StoreToken(requestCtx, token, time.Duration(role.TokenTTL)*time.Second, record, jc.Redis)
+	if err != nil {
+		jc.Logger.LogError(requestCtx, "failed to store token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	// Record the issued token's accessor in the request log
+	ctx.Set("token_accessor", stored.Accessor)
+
+	response := models.IssueTokenResponse{
+		Token:    token,
+		Accessor: stored.Accessor,
+		TTL:      role.TokenTTL,
+		MaxTTL:   role.TokenMaxTTL,
+		NumUses:  role.TokenNumUses,
+		Policies: role.Policies,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
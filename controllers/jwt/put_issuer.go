@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create or update a JWT issuer
+// @Description Configures a trusted JWT issuer and where its key set is loaded from, a JWKS URL or a JWKS file on the server. The key set is loaded once to check it. Requires the root token.
+// @Tags sys
+// @Accept json
+// @Produce json
+// @Param name path string true "Issuer name"
+// @Param body body models.JWTIssuerRequest true "JWT issuer"
+// @Security BearerAuth
+// @Success 200 {object} models.JWTIssuerResponse "Stored JWT issuer"
+// @Failure 400 {object} models.ErrorResponse "Invalid issuer name, configuration or key set"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/jwt/issuers/{name} [put]
+func (jc *JWTControllerImpl) PutIssuer(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var request models.JWTIssuerRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		jc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	issuer := internal.JWTIssuer{
+		Name:     ctx.Param("name"),
+		Issuer:   request.Issuer,
+		JWKSURL:  request.JWKSURL,
+		JWKSFile: request.JWKSFile,
+	}
+	if err := jc.JWTAuth.PutIssuer(requestCtx, issuer); err != nil {
+		switch {
+		case stderrors.Is(err, internal.ErrInvalidKeyName):
+			jc.Logger.LogWarn(requestCtx, "invalid jwt issuer name", requestID, err)
+			errors.ErrInvalidKeyName.WithRequestID(ctx).JSON(ctx)
+		case stderrors.Is(err, internal.ErrInvalidJWTConfig):
+			jc.Logger.LogWarn(requestCtx, "invalid jwt issuer", requestID, err)
+			errors.ErrInvalidJWTConfig.WithRequestID(ctx).JSON(ctx)
+		default:
+			jc.Logger.LogError(requestCtx, "failed to store jwt issuer", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, issuerResponse(&issuer))
+}
+
+// issuerResponse builds the response describing a JWT issuer.
+func issuerResponse(issuer *internal.JWTIssuer) models.JWTIssuerResponse {
+	return models.JWTIssuerResponse{
+		Name:     issuer.Name,
+		Issuer:   issuer.Issuer,
+		JWKSURL:  issuer.JWKSURL,
+		JWKSFile: issuer.JWKSFile,
+	}
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	stderrors "errors"
+	tokenControllers "go-secrets/controllers/token"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create or update a JWT role
+// @Description Stores a named JWT role that maps JWTs of an issuer matching its bound audiences, subject and claims to tokens with its policies, TTL and use limit. Requires the root token.
+// @Tags sys
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param body body models.JWTRoleRequest true "JWT role"
+// @Security BearerAuth
+// @Success 200 {object} models.JWTRoleResponse "Stored JWT role"
+// @Failure 400 {object} models.ErrorResponse "Invalid role name, TTLs, claim rules, unknown issuer or policy"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/jwt/roles/{name} [put]
+func (jc *JWTControllerImpl) PutRole(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var request models.JWTRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		jc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if request.TokenTTL == 0 {
+		request.TokenTTL = tokenControllers.DefaultTTL
+	}
+	if request.TokenMaxTTL == 0 {
+		request.TokenMaxTTL = max(tokenControllers.DefaultMaxTTL, request.TokenTTL)
+	}
+	if request.TokenTTL < 0 || request.TokenTTL > tokenControllers.MaxTTL ||
+		request.TokenMaxTTL < request.TokenTTL || request.TokenMaxTTL > tokenControllers.MaxRenewableTTL ||
+		request.TokenNumUses < 0 {
+		jc.Logger.LogWarn(requestCtx, "invalid jwt role ttl or use limit", requestID, nil)
+		errors.ErrInvalidJWTConfig.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	for _, name := range request.Policies {
+		if _, err := jc.Policies.Get(requestCtx, name); stderrors.Is(err, internal.ErrPolicyNotFound) {
+			jc.Logger.LogWarn(requestCtx, "policy not found", requestID, err)
+			errors.ErrPolicyNotFound.WithRequestID(ctx).JSON(ctx)
+			return
+		} else if err != nil {
+			jc.Logger.LogError(requestCtx, "failed to get policy", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+
+	role, err := jc.JWTAuth.PutRole(requestCtx, internal.JWTRole{
+		Name:           ctx.Param("name"),
+		Issuer:         request.Issuer,
+		BoundAudiences: request.BoundAudiences,
+		BoundSubject:   request.BoundSubject,
+		BoundClaims:    request.BoundClaims,
+		UserClaim:      request.UserClaim,
+		ClaimMappings:  request.ClaimMappings,
+		Policies:       request.Policies,
+		TokenTTL:       request.TokenTTL,
+		TokenMaxTTL:    request.TokenMaxTTL,
+		TokenNumUses:   request.TokenNumUses,
+	})
+	if err != nil {
+		switch {
+		case stderrors.Is(err, internal.ErrInvalidKeyName):
+			jc.Logger.LogWarn(requestCtx, "invalid jwt role name", requestID, err)
+			errors.ErrInvalidKeyName.WithRequestID(ctx).JSON(ctx)
+		case stderrors.Is(err, internal.ErrInvalidJWTConfig):
+			jc.Logger.LogWarn(requestCtx, "invalid jwt role", requestID, err)
+			errors.ErrInvalidJWTConfig.WithRequestID(ctx).JSON(ctx)
+		default:
+			jc.Logger.LogError(requestCtx, "failed to store jwt role", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roleResponse(role))
+}
+
+// roleResponse builds the response describing a JWT role.
+func roleResponse(role *internal.JWTRole) models.JWTRoleResponse {
+	return models.JWTRoleResponse{
+		Name:           role.Name,
+		Issuer:         role.Issuer,
+		BoundAudiences: role.BoundAudiences,
+		BoundSubject:   role.BoundSubject,
+		BoundClaims:    role.BoundClaims,
+		UserClaim:      role.UserClaim,
+		ClaimMappings:  role.ClaimMappings,
+		Policies:       role.Policies,
+		TokenTTL:       role.TokenTTL,
+		TokenMaxTTL:    role.TokenMaxTTL,
+		TokenNumUses:   role.TokenNumUses,
+	}
+}
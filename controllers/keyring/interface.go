@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"go-secrets/internal"
+	"go-secrets/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KeyringController interface {
+	Status(ctx *gin.Context)
+	Rotate(ctx *gin.Context)
+	StartRewrap(ctx *gin.Context)
+	Retire(ctx *gin.Context)
+}
+
+type KeyringControllerImpl struct {
+	Logger  internal.LoggerService
+	Keyring internal.KeyringService
+	Rewrap  internal.RewrapService
+}
+
+// statusResponse builds the keyring status response from the keyring and the rewrap job status.
+func (kc *KeyringControllerImpl) statusResponse() (models.KeyringStatusResponse, error) {
+	keys, err := kc.Keyring.Keys()
+	if err != nil {
+		return models.KeyringStatusResponse{}, err
+	}
+
+	activeKey, err := kc.Keyring.ActiveKey()
+	if err != nil {
+		return models.KeyringStatusResponse{}, err
+	}
+
+	response := models.KeyringStatusResponse{
+		ActiveVersion: activeKey.Version,
+		Keys:          make([]models.KeyringKeyResponse, 0, len(keys)),
+	}
+	for _, key := range keys {
+		response.Keys = append(response.Keys, models.KeyringKeyResponse{
+			Version:   key.Version,
+			CreatedAt: key.CreatedAt,
+			Active:    key.Version == activeKey.Version,
+		})
+	}
+
+	status := kc.Rewrap.Status()
+	response.Rewrap = models.RewrapStatusResponse{
+		Running:       status.Running,
+		TargetVersion: status.TargetVersion,
+		StartedAt:     optionalTime(status.StartedAt),
+		FinishedAt:    optionalTime(status.FinishedAt),
+		Scanned:       status.Scanned,
+		Reencrypted:   status.Reencrypted,
+		Failed:        status.Failed,
+		Error:         status.Error,
+	}
+	if len(status.PendingTokens) > 0 {
+		response.Rewrap.PendingTokens = make(map[string]int, len(status.PendingTokens))
+		for version, count := range status.PendingTokens {
+			response.Rewrap.PendingTokens[strconv.Itoa(version)] = count
+		}
+	}
+
+	return response, nil
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Retire a root key version
+// @Description Removes a root key version once no token record or secret references it anymore. Requires the root token.
+// @Tags sys
+// @Param version path int true "Key version"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse "Invalid key version"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Key version not found"
+// @Failure 409 {object} models.ErrorResponse "Key version is active or still in use"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/keyring/{version} [delete]
+func (kc *KeyringControllerImpl) Retire(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		kc.Logger.LogWarn(requestCtx, "invalid key version", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	activeKey, err := kc.Keyring.ActiveKey()
+	if err != nil {
+		kc.Logger.LogError(requestCtx, "failed to get active key", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+	if version == activeKey.Version {
+		kc.Logger.LogWarn(requestCtx, "attempted to retire active key version", requestID, nil)
+		errors.ErrKeyVersionActive.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if _, err := kc.Keyring.Key(version); err != nil {
+		kc.Logger.LogWarn(requestCtx, "key version not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	references, err := kc.Rewrap.References(requestCtx, version)
+	if err != nil {
+		kc.Logger.LogError(requestCtx, "failed to count key version references", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+	if references > 0 {
+		kc.Logger.LogWarn(requestCtx, "key version is still in use", requestID, nil)
+		errors.ErrKeyVersionInUse.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if err := kc.Keyring.Retire(requestCtx, version); err != nil {
+		if stderrors.Is(err, internal.ErrKeyVersionActive) {
+			errors.ErrKeyVersionActive.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+		kc.Logger.LogError(requestCtx, "failed to retire key version", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"go-secrets/internal"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubKeyring holds the given versions, the highest of them active, and records the versions retired.
+type stubKeyring struct {
+	internal.KeyringService
+	versions []int
+	retired  []int
+}
+
+func (s *stubKeyring) ActiveKey() (*internal.KeyringKey, error) {
+	return &internal.KeyringKey{Version: s.versions[len(s.versions)-1]}, nil
+}
+
+func (s *stubKeyring) Key(version int) (*internal.KeyringKey, error) {
+	for _, v := range s.versions {
+		if v == version {
+			return &internal.KeyringKey{Version: version}, nil
+		}
+	}
+	return nil, internal.ErrKeyVersionNotFound
+}
+
+func (s *stubKeyring) Retire(ctx context.Context, version int) error {
+	s.retired = append(s.retired, version)
+	return nil
+}
+
+// stubReferences reports a fixed number of references per key version.
+type stubReferences struct {
+	internal.RewrapService
+	references map[int]int
+}
+
+func (s *stubReferences) References(ctx context.Context, version int) (int, error) {
+	return s.references[version], nil
+}
+
+func TestRetire(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		version string
+		status  int
+	}{
+		{name: "retires an unreferenced version", version: "1", status: http.StatusNoContent},
+		{name: "refuses a version still in use", version: "2", status: http.StatusConflict},
+		{name: "refuses the active version", version: "3", status: http.StatusConflict},
+		{name: "rejects unknown versions", version: "9", status: http.StatusNotFound},
+		{name: "rejects invalid versions", version: "latest", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring := &stubKeyring{versions: []int{1, 2, 3}}
+			controller := &KeyringControllerImpl{
+				Logger:  internal.NewLogger(slog.LevelError, io.Discard),
+				Keyring: keyring,
+				Rewrap:  &stubReferences{references: map[int]int{2: 4, 3: 10}},
+			}
+			router := gin.New()
+			router.DELETE("/sys/keyring/:version", controller.Retire)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/sys/keyring/"+tt.version, nil))
+
+			require.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			if tt.status == http.StatusNoContent {
+				assert.Equal(t, []int{1}, keyring.retired)
+			} else {
+				assert.Empty(t, keyring.retired)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"go-secrets/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Start a rewrap job
+// @Description Re-encrypts stored secrets that use an older key version or another algorithm than the configured one. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} models.KeyringStatusResponse "Rewrap job started"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 409 {object} models.ErrorResponse "Rewrap job already running"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/keyring/rewrap [post]
+func (kc *KeyringControllerImpl) StartRewrap(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	started, err := kc.Rewrap.Start()
+	if err != nil {
+		kc.Logger.LogError(requestCtx, "failed to start rewrap job", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+	if !started {
+		kc.Logger.LogWarn(requestCtx, "rewrap job is already running", requestID, nil)
+		errors.ErrRewrapRunning.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	response, err := kc.statusResponse()
+	if err != nil {
+		kc.Logger.LogError(requestCtx, "failed to get keyring status", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, response)
+}
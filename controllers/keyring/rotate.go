@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"go-secrets/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Rotate the root key
+// @Description Adds a new root key version and starts re-encrypting stored secrets with it in the background. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.KeyringStatusResponse "Keyring status after rotation"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 409 {object} models.ErrorResponse "Rewrap job already running"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/keyring/rotate [post]
+func (kc *KeyringControllerImpl) Rotate(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	if kc.Rewrap.Status().Running {
+		kc.Logger.LogWarn(requestCtx, "rewrap job is already running", requestID, nil)
+		errors.ErrRewrapRunning.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if _, err := kc.Keyring.Rotate(requestCtx); err != nil {
+		kc.Logger.LogError(requestCtx, "failed to rotate root key", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if _, err := kc.Rewrap.Start(); err != nil {
+		kc.Logger.LogError(requestCtx, "failed to start rewrap job", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	response, err := kc.statusResponse()
+	if err != nil {
+		kc.Logger.LogError(requestCtx, "failed to get keyring status", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"go-secrets/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get keyring status
+// @Description Lists the root key versions and the progress of the rewrap job. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.KeyringStatusResponse "Keyring status"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/keyring [get]
+func (kc *KeyringControllerImpl) Status(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	response, err := kc.statusResponse()
+	if err != nil {
+		kc.Logger.LogError(requestCtx, "failed to get keyring status", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
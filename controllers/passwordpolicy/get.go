@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a password policy
+// @Description Returns a named password policy. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Param name path string true "Policy name"
+// @Security BearerAuth
+// @Success 200 {object} helpers.PasswordPolicy "Password policy"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "Password policy not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/password-policies/{name} [get]
+func (pc *PasswordPolicyControllerImpl) Get(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	policy, err := pc.PasswordPolicies.Get(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrPasswordPolicyNotFound) {
+		pc.Logger.LogWarn(requestCtx, "password policy not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		pc.Logger.LogError(requestCtx, "failed to get password policy", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+}
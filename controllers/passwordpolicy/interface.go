@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"go-secrets/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordPolicyController interface {
+	Put(ctx *gin.Context)
+	Get(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type PasswordPolicyControllerImpl struct {
+	Logger           internal.LoggerService
+	PasswordPolicies internal.PasswordPolicyService
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/helpers"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create or update a password policy
+// @Description Stores a named password policy that secrets can reference in their generate spec. Requires the root token.
+// @Tags sys
+// @Accept json
+// @Produce json
+// @Param name path string true "Policy name"
+// @Param body body helpers.PasswordPolicy true "Password policy"
+// @Security BearerAuth
+// @Success 200 {object} helpers.PasswordPolicy "Stored password policy"
+// @Failure 400 {object} models.ErrorResponse "Invalid policy name or password policy"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/password-policies/{name} [put]
+func (pc *PasswordPolicyControllerImpl) Put(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var policy helpers.PasswordPolicy
+	if err := ctx.ShouldBindJSON(&policy); err != nil {
+		pc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if err := pc.PasswordPolicies.Put(requestCtx, ctx.Param("name"), policy); err != nil {
+		switch {
+		case stderrors.Is(err, internal.ErrInvalidKeyName):
+			pc.Logger.LogWarn(requestCtx, "invalid password policy name", requestID, err)
+			errors.ErrInvalidKeyName.WithRequestID(ctx).JSON(ctx)
+		case stderrors.Is(err, internal.ErrInvalidPasswordPolicy):
+			pc.Logger.LogWarn(requestCtx, "invalid password policy", requestID, err)
+			errors.ErrInvalidPasswordPolicy.WithRequestID(ctx).JSON(ctx)
+		default:
+			pc.Logger.LogError(requestCtx, "failed to store password policy", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Delete a token policy
+// @Description Deletes a named token policy. Tokens it was attached to lose the rights it granted. Requires the root token.
+// @Tags sys
+// @Param name path string true "Policy name"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "Policy not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/policies/{name} [delete]
+func (pc *PolicyControllerImpl) Delete(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	err := pc.Policies.Delete(requestCtx, ctx.Param("name"))
+	if stderrors.Is(err, internal.ErrPolicyNotFound) {
+		pc.Logger.LogWarn(requestCtx, "policy not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		pc.Logger.LogError(requestCtx, "failed to delete policy", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"go-secrets/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyController interface {
+	Put(ctx *gin.Context)
+	Get(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type PolicyControllerImpl struct {
+	Logger   internal.LoggerService
+	Policies internal.PolicyService
+}
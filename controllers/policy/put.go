@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/helpers"
+	"go-secrets/internal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create or update a token policy
+// @Description Stores a named token policy of path globs and the capabilities granted on them, which tokens can be issued with. Requires the root token.
+// @Tags sys
+// @Accept json
+// @Produce json
+// @Param name path string true "Policy name"
+// @Param body body helpers.Policy true "Policy"
+// @Security BearerAuth
+// @Success 200 {object} helpers.Policy "Stored policy"
+// @Failure 400 {object} models.ErrorResponse "Invalid policy name or policy"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/policies/{name} [put]
+func (pc *PolicyControllerImpl) Put(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var policy helpers.Policy
+	if err := ctx.ShouldBindJSON(&policy); err != nil {
+		pc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if err := pc.Policies.Put(requestCtx, ctx.Param("name"), policy); err != nil {
+		switch {
+		case stderrors.Is(err, internal.ErrInvalidKeyName):
+			pc.Logger.LogWarn(requestCtx, "invalid policy name", requestID, err)
+			errors.ErrInvalidKeyName.WithRequestID(ctx).JSON(ctx)
+		case stderrors.Is(err, internal.ErrInvalidPolicy):
+			pc.Logger.LogWarn(requestCtx, "invalid policy", requestID, err)
+			errors.ErrInvalidPolicy.WithRequestID(ctx).JSON(ctx)
+		default:
+			pc.Logger.LogError(requestCtx, "failed to store policy", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"go-secrets/internal"
+	"go-secrets/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SealController interface {
+	Status(ctx *gin.Context)
+	Unseal(ctx *gin.Context)
+	SealServer(ctx *gin.Context)
+}
+
+type SealControllerImpl struct {
+	Logger internal.LoggerService
+	Seal   internal.SealService
+}
+
+// statusResponse converts the seal status into its response payload.
+func statusResponse(status internal.SealStatus) models.SealStatusResponse {
+	return models.SealStatusResponse{
+		Sealed:         status.Sealed,
+		Type:           string(status.Type),
+		Shares:         status.Shares,
+		Threshold:      status.Threshold,
+		Progress:       status.Progress,
+		KeyVersion:     status.KeyVersion,
+		KeyFingerprint: status.KeyFingerprint,
+	}
+}
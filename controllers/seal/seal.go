@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Seal the server
+// @Description Unloads the keyring and wipes the master key from memory. Token and secret routes return 503 until the server is unsealed again. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SealStatusResponse "Seal status"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Router /sys/seal [post]
+func (sc *SealControllerImpl) SealServer(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	sc.Seal.Seal()
+	sc.Logger.Log(requestCtx, slog.LevelInfo, "server sealed", requestID, nil)
+
+	ctx.JSON(http.StatusOK, statusResponse(sc.Seal.Status()))
+}
@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get seal status
+// @Description Reports whether the server is sealed and how many unseal key shares have been submitted
+// @Tags sys
+// @Produce json
+// @Success 200 {object} models.SealStatusResponse "Seal status"
+// @Router /sys/seal-status [get]
+func (sc *SealControllerImpl) Status(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, statusResponse(sc.Seal.Status()))
+}
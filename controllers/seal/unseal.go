@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"encoding/hex"
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Submit an unseal key
+// @Description Submits one hex encoded unseal key share, or the master key when the server uses a master key file. The server is unsealed once the threshold of shares is reached. Set reset to discard the shares submitted so far.
+// @Tags sys
+// @Accept json
+// @Produce json
+// @Param body body models.UnsealRequest true "Unseal key"
+// @Success 200 {object} models.SealStatusResponse "Seal status"
+// @Failure 400 {object} models.ErrorResponse "Invalid unseal key"
+// @Failure 500 {object} models.ErrorResponse "Key material does not match the store or internal server error"
+// @Router /sys/unseal [post]
+func (sc *SealControllerImpl) Unseal(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var req models.UnsealRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		sc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if req.Reset {
+		ctx.JSON(http.StatusOK, statusResponse(sc.Seal.ResetUnseal()))
+		return
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(req.Key))
+	if err != nil || len(key) == 0 {
+		sc.Logger.LogWarn(requestCtx, "invalid unseal key encoding", requestID, err)
+		errors.ErrInvalidUnsealKey.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	wasSealed := sc.Seal.Sealed()
+	status, err := sc.Seal.Unseal(requestCtx, key)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, internal.ErrInvalidUnsealKey):
+			sc.Logger.LogWarn(requestCtx, "invalid unseal key", requestID, err)
+			errors.ErrInvalidUnsealKey.WithRequestID(ctx).JSON(ctx)
+		case stderrors.Is(err, internal.ErrUnsealKeyAlreadyGiven):
+			sc.Logger.LogWarn(requestCtx, "unseal key share submitted twice", requestID, err)
+			errors.ErrUnsealKeyAlreadyGiven.WithRequestID(ctx).JSON(ctx)
+		case stderrors.Is(err, internal.ErrCanaryMismatch):
+			sc.Logger.LogError(requestCtx, "key material does not match the store", requestID, err)
+			errors.ErrKeyMaterialMismatch.WithRequestID(ctx).JSON(ctx)
+		default:
+			sc.Logger.LogError(requestCtx, "failed to unseal", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		}
+		return
+	}
+
+	if wasSealed && !status.Sealed {
+		sc.Logger.Log(requestCtx, slog.LevelInfo, "server unsealed", requestID, nil)
+	}
+
+	ctx.JSON(http.StatusOK, statusResponse(status))
+}
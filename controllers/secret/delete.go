@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	stderrors "errors"
 	"go-secrets/errors"
 	"go-secrets/helpers"
+	"go-secrets/internal"
 	"net/http"
 	"strings"
 
@@ -30,25 +32,36 @@ func (sc *SecretsControllerImpl) Delete(ctx *gin.Context) {
 		return
 	}
 
-	tokenHMAC, err := sc.Token.AuthTokenHMAC(ctx)
+	namespace, err := sc.Token.AuthTokenNamespace(ctx)
 	if err != nil {
-		sc.Logger.LogError(requestCtx, "failed to get token hmac", requestID, err)
+		sc.Logger.LogError(requestCtx, "failed to get token namespace", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
 	}
 
-	secretPath, err := helpers.FormatSecretPath(tokenHMAC, secretKeyPath)
+	secretPath, err := helpers.FormatSecretPath(namespace, secretKeyPath)
 	if err != nil {
 		sc.Logger.LogError(requestCtx, "failed to format secret path", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
 	}
 
+	previous, err := sc.Redis.Get(requestCtx, secretPath)
+	if err != nil && !stderrors.Is(err, internal.ErrKeyNotFound) {
+		sc.Logger.LogError(requestCtx, "failed to get secret", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
 	if err := sc.Redis.Del(requestCtx, secretPath); err != nil {
 		sc.Logger.LogError(requestCtx, "failed to delete secret", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
 	}
 
+	if err := sc.Blobs.Discard(requestCtx, secretPath, previous); err != nil {
+		sc.Logger.LogWarn(requestCtx, "failed to delete chunks of binary secret", requestID, err)
+	}
+
 	ctx.Status(http.StatusNoContent)
 }
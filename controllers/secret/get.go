@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	stderrors "errors"
 	"go-secrets/errors"
 	"go-secrets/helpers"
+	"go-secrets/internal"
 	"go-secrets/models"
 	"net/http"
 	"strings"
@@ -11,19 +13,28 @@ import (
 )
 
 // @Summary Retrieve a secret
-// @Description Gets a secret by key path
+// @Description Gets a secret by key path, or lists the keys under the path when list is true. Values encrypted by the client are returned as stored, flagged as client encrypted. Binary secrets are streamed as the raw response body with their content type, and their TTL in the X-Secret-TTL header.
 // @Tags secret
+// @Produce json
+// @Produce octet-stream
 // @Param key path string true "Secret key"
+// @Param list query bool false "List the keys starting with the key path instead"
 // @Security BearerAuth
 // @Success 200 {object} models.GetSecretResponse "Secret retrieved"
+// @Success 200 {object} models.ListSecretsResponse "Keys listed"
 // @Failure 400 {object} models.ErrorResponse "Missing key path"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 500 {object} models.ErrorResponse "Internal server error or secret integrity check failed"
 // @Router /secret/{key} [get]
 func (sc *SecretsControllerImpl) Get(ctx *gin.Context) {
 	requestCtx := ctx.Request.Context()
 	requestID := ctx.GetString("request_id")
 	fullPath := ctx.Param("key")
 
+	if ctx.Query("list") == "true" {
+		sc.List(ctx)
+		return
+	}
+
 	secretKeyPath := strings.TrimPrefix(fullPath, "/")
 	if secretKeyPath == "" {
 		sc.Logger.LogWarn(requestCtx, "missing secret key path", requestID, nil)
@@ -31,21 +42,14 @@ func (sc *SecretsControllerImpl) Get(ctx *gin.Context) {
 		return
 	}
 
-	token, err := sc.Token.GetHeaderToken(ctx)
-	if err != nil {
-		sc.Logger.LogError(requestCtx, "failed to get token from header", requestID, err)
-		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
-		return
-	}
-
-	tokenHMAC, err := sc.Token.AuthTokenHMAC(ctx)
+	namespace, err := sc.Token.AuthTokenNamespace(ctx)
 	if err != nil {
-		sc.Logger.LogError(requestCtx, "failed to get token hmac", requestID, err)
+		sc.Logger.LogError(requestCtx, "failed to get token namespace", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
 	}
 
-	secretPath, err := helpers.FormatSecretPath(tokenHMAC, secretKeyPath)
+	secretPath, err := helpers.FormatSecretPath(namespace, secretKeyPath)
 	if err != nil {
 		sc.Logger.LogError(requestCtx, "failed to format secret path", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
@@ -66,8 +70,26 @@ func (sc *SecretsControllerImpl) Get(ctx *gin.Context) {
 		return
 	}
 
-	decryptedValue, err := sc.Crypto.Decrypt(encryptedValue, token)
-	if err != nil {
+	if internal.IsBlob(encryptedValue) {
+		sc.getBinary(ctx, secretPath, encryptedValue, ttl)
+		return
+	}
+
+	if internal.IsClientEncrypted(encryptedValue) {
+		ctx.JSON(http.StatusOK, models.GetSecretResponse{
+			Value:           strings.TrimPrefix(encryptedValue, internal.ClientEncryptedPrefix),
+			TTL:             int(ttl.Seconds()),
+			ClientEncrypted: true,
+		})
+		return
+	}
+
+	decryptedValue, err := sc.Crypto.Decrypt(encryptedValue, secretPath)
+	if stderrors.Is(err, internal.ErrTampered) {
+		sc.Logger.LogError(requestCtx, "secret failed authentication, possible tampering", requestID, err)
+		errors.ErrSecretTampered.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
 		sc.Logger.LogError(requestCtx, "failed to decrypt secret", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getBinary streams a binary secret as the raw response body. Once streaming has started the status can no longer
+// change, so a chunk failing to decrypt ends the response early, shorter than its Content-Length, which makes the
+// server close the connection and clients report a truncated body.
+func (sc *SecretsControllerImpl) getBinary(ctx *gin.Context, secretPath string, stored string, ttl time.Duration) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	manifest, err := sc.Blobs.Manifest(secretPath, stored)
+	if stderrors.Is(err, internal.ErrTampered) {
+		sc.Logger.LogError(requestCtx, "binary secret failed authentication, possible tampering", requestID, err)
+		errors.ErrSecretTampered.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		sc.Logger.LogError(requestCtx, "failed to decrypt binary secret", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", manifest.ContentType)
+	ctx.Header("Content-Length", strconv.FormatInt(manifest.Size, 10))
+	ctx.Header("X-Secret-TTL", strconv.Itoa(int(ttl.Seconds())))
+	ctx.Status(http.StatusOK)
+
+	if err := sc.Blobs.Stream(requestCtx, secretPath, manifest, ctx.Writer); err != nil {
+		sc.Logger.LogError(requestCtx, "failed to stream binary secret", requestID, err)
+		ctx.Abort()
+	}
+}
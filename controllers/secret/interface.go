@@ -8,13 +8,17 @@ import (
 
 type SecretsController interface {
 	Get(ctx *gin.Context)
+	List(ctx *gin.Context)
 	Set(ctx *gin.Context)
 	Delete(ctx *gin.Context)
 }
 
 type SecretsControllerImpl struct {
-	Logger internal.LoggerService
-	Crypto internal.CryptoService
-	Redis  internal.RedisService
-	Token  internal.TokenService
+	Logger           internal.LoggerService
+	Crypto           internal.CryptoService
+	Redis            internal.RedisService
+	Token            internal.TokenService
+	PasswordPolicies internal.PasswordPolicyService
+	Blobs            internal.BlobService
+	MaxValueSize     int64
 }
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"go-secrets/errors"
+	"go-secrets/helpers"
+	"go-secrets/models"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redisGlobEscaper escapes the characters with a special meaning in Redis match patterns.
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// List returns the keys of the token's secrets starting with the key path, which may be empty to list them all.
+// It is served by Get for requests with list=true.
+func (sc *SecretsControllerImpl) List(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+	prefix := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	namespace, err := sc.Token.AuthTokenNamespace(ctx)
+	if err != nil {
+		sc.Logger.LogError(requestCtx, "failed to get token namespace", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	iter, err := sc.Redis.NewScanner(requestCtx, redisGlobEscaper.Replace(namespace+":secret:"+prefix)+"*")
+	if err != nil {
+		sc.Logger.LogError(requestCtx, "failed to create scanner", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	keys := []string{}
+	for iter.Next(requestCtx) {
+		_, key, err := helpers.ParseSecretPath(iter.Val())
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	if err := iter.Err(); err != nil {
+		sc.Logger.LogError(requestCtx, "failed to scan secrets", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	slices.Sort(keys)
+	ctx.JSON(http.StatusOK, models.ListSecretsResponse{Keys: keys})
+}
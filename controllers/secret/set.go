@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	stderrors "errors"
 	"go-secrets/errors"
 	"go-secrets/helpers"
+	"go-secrets/internal"
 	"go-secrets/models"
 	"net/http"
 	"strings"
@@ -11,15 +13,18 @@ import (
 )
 
 // @Summary Store a secret
-// @Description Stores a secret with a key path
+// @Description Stores a secret with a key path. Instead of a value, a generate spec or the name of a password policy can be given to generate the value on the server, which is returned once in the response. Values flagged as client encrypted are stored as opaque blobs without server-side encryption. Binary secrets are uploaded as the raw request body with their content type, or as the `file` field of a multipart form, and encrypted while streaming.
 // @Tags secret
 // @Accept json
+// @Accept octet-stream
+// @Accept mpfd
 // @Produce json
 // @Param key path string true "Secret key"
 // @Param body body models.StoreSecretRequest true "Secret data"
 // @Security BearerAuth
 // @Success 200 {object} models.StoreSecretResponse "Secret stored"
-// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 400 {object} models.ErrorResponse "Invalid request or password policy"
+// @Failure 413 {object} models.ErrorResponse "Secret exceeds the maximum size"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /secret/{key} [post]
 func (sc *SecretsControllerImpl) Set(ctx *gin.Context) {
@@ -34,26 +39,86 @@ func (sc *SecretsControllerImpl) Set(ctx *gin.Context) {
 		return
 	}
 
-	token, err := sc.Token.GetHeaderToken(ctx)
+	tokenHMAC, err := sc.Token.AuthTokenHMAC(ctx)
 	if err != nil {
-		sc.Logger.LogError(requestCtx, "failed to get token from header", requestID, err)
+		sc.Logger.LogError(requestCtx, "failed to get token hmac", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
 	}
 
-	tokenHMAC, err := sc.Token.AuthTokenHMAC(ctx)
+	namespace, err := sc.Token.AuthTokenNamespace(ctx)
 	if err != nil {
-		sc.Logger.LogError(requestCtx, "failed to get token hmac", requestID, err)
+		sc.Logger.LogError(requestCtx, "failed to get token namespace", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
 	}
 
+	if isBinaryUpload(ctx) {
+		sc.setBinary(ctx, tokenHMAC, namespace, secretKeyPath)
+		return
+	}
+
 	var req models.StoreSecretRequest
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, sc.MaxValueSize)
 	if err := ctx.ShouldBindJSON(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if stderrors.As(err, &maxBytesErr) {
+			sc.Logger.LogWarn(requestCtx, "secret exceeds the maximum size", requestID, err)
+			errors.ErrSecretTooLarge.WithRequestID(ctx).JSON(ctx)
+			return
+		}
 		sc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
 		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
 		return
 	}
+	if (req.Value == "") == (req.Generate == nil) {
+		sc.Logger.LogWarn(requestCtx, "request must contain either a value or a generate spec", requestID, nil)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+	if req.ClientEncrypted && req.Generate != nil {
+		sc.Logger.LogWarn(requestCtx, "client encrypted values cannot be generated", requestID, nil)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	value := req.Value
+	if req.Generate != nil {
+		policy := req.Generate.PasswordPolicy
+		if req.Generate.Policy != "" {
+			if !policy.IsEmpty() {
+				sc.Logger.LogWarn(requestCtx, "generate spec cannot combine a named policy with inline options", requestID, nil)
+				errors.ErrInvalidPasswordPolicy.WithRequestID(ctx).JSON(ctx)
+				return
+			}
+
+			var err error
+			policy, err = sc.PasswordPolicies.Get(requestCtx, req.Generate.Policy)
+			if stderrors.Is(err, internal.ErrPasswordPolicyNotFound) {
+				sc.Logger.LogWarn(requestCtx, "password policy not found", requestID, err)
+				errors.ErrPasswordPolicyNotFound.WithRequestID(ctx).JSON(ctx)
+				return
+			} else if err != nil {
+				sc.Logger.LogError(requestCtx, "failed to get password policy", requestID, err)
+				errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+				return
+			}
+		}
+
+		if err := policy.Validate(); err != nil {
+			sc.Logger.LogWarn(requestCtx, "invalid generate spec", requestID, err)
+			errors.ErrInvalidPasswordPolicy.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+
+		generated, err := helpers.GenerateValue(policy)
+		if err != nil {
+			sc.Logger.LogError(requestCtx, "failed to generate secret value", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+		value = generated
+	}
 
 	ttl, err := sc.Redis.TTL(requestCtx, tokenHMAC)
 	if err != nil || ttl <= 0 {
@@ -62,16 +127,26 @@ func (sc *SecretsControllerImpl) Set(ctx *gin.Context) {
 		return
 	}
 
-	encryptedValue, err := sc.Crypto.Encrypt(req.Value, token)
+	secretPath, err := helpers.FormatSecretPath(namespace, secretKeyPath)
 	if err != nil {
-		sc.Logger.LogError(requestCtx, "encryption failed", requestID, err)
+		sc.Logger.LogError(requestCtx, "failed to generate secret key", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
 	}
 
-	secretPath, err := helpers.FormatSecretPath(tokenHMAC, secretKeyPath)
-	if err != nil {
-		sc.Logger.LogError(requestCtx, "failed to generate secret key", requestID, err)
+	encryptedValue := internal.ClientEncryptedPrefix + value
+	if !req.ClientEncrypted {
+		encryptedValue, err = sc.Crypto.Encrypt(value, secretPath)
+		if err != nil {
+			sc.Logger.LogError(requestCtx, "encryption failed", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+
+	previous, err := sc.Redis.Get(requestCtx, secretPath)
+	if err != nil && !stderrors.Is(err, internal.ErrKeyNotFound) {
+		sc.Logger.LogError(requestCtx, "failed to get secret", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
 	}
@@ -83,10 +158,17 @@ func (sc *SecretsControllerImpl) Set(ctx *gin.Context) {
 		return
 	}
 
+	if err := sc.Blobs.Discard(requestCtx, secretPath, previous); err != nil {
+		sc.Logger.LogWarn(requestCtx, "failed to delete chunks of replaced binary secret", requestID, err)
+	}
+
 	response := models.StoreSecretResponse{
 		Key: secretKeyPath,
 		TTL: int(ttl.Seconds()),
 	}
+	if req.Generate != nil {
+		response.Value = value
+	}
 
 	ctx.JSON(http.StatusOK, response)
 }
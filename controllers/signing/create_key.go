@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create a signing key
+// @Description Generates a named Ed25519 or ECDSA P-256 key whose private key never leaves the server. Requires the root token.
+// @Tags signing
+// @Accept json
+// @Produce json
+// @Param name path string true "Key name"
+// @Param body body models.CreateSigningKeyRequest true "Key type"
+// @Security BearerAuth
+// @Success 201 {object} models.SigningKeyResponse "Key created"
+// @Failure 400 {object} models.ErrorResponse "Invalid key name or type"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 409 {object} models.ErrorResponse "Key already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /signing/keys/{name} [post]
+func (sc *SigningControllerImpl) CreateKey(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var req models.CreateSigningKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		sc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	key, err := sc.Signing.CreateKey(requestCtx, ctx.Param("name"), internal.SigningKeyType(req.Type))
+	if err != nil {
+		sc.respondError(ctx, "failed to create signing key", err)
+		return
+	}
+
+	response, err := keyResponse(key)
+	if err != nil {
+		sc.respondError(ctx, "failed to export public key", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
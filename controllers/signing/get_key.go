@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Export a public key
+// @Description Returns the type and PEM encoded public key of a signing key
+// @Tags signing
+// @Produce json
+// @Param name path string true "Key name"
+// @Security BearerAuth
+// @Success 200 {object} models.SigningKeyResponse "Key"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /signing/keys/{name} [get]
+func (sc *SigningControllerImpl) GetKey(ctx *gin.Context) {
+	key, err := sc.Signing.Key(ctx.Request.Context(), ctx.Param("name"))
+	if err != nil {
+		sc.respondError(ctx, "failed to get signing key", err)
+		return
+	}
+
+	response, err := keyResponse(key)
+	if err != nil {
+		sc.respondError(ctx, "failed to export public key", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
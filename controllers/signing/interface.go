@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SigningController interface {
+	CreateKey(ctx *gin.Context)
+	GetKey(ctx *gin.Context)
+	Sign(ctx *gin.Context)
+	Verify(ctx *gin.Context)
+}
+
+type SigningControllerImpl struct {
+	Logger  internal.LoggerService
+	Signing internal.SigningService
+}
+
+// respondError logs the error of a signing operation and responds with the matching error response.
+func (sc *SigningControllerImpl) respondError(ctx *gin.Context, message string, err error) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	switch {
+	case stderrors.Is(err, internal.ErrSigningKeyNotFound):
+		sc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+	case stderrors.Is(err, internal.ErrInvalidKeyName):
+		sc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrInvalidKeyName.WithRequestID(ctx).JSON(ctx)
+	case stderrors.Is(err, internal.ErrSigningKeyExists):
+		sc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrKeyExists.WithRequestID(ctx).JSON(ctx)
+	case stderrors.Is(err, internal.ErrUnsupportedSigningKeyType):
+		sc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrUnsupportedKeyType.WithRequestID(ctx).JSON(ctx)
+	default:
+		sc.Logger.LogError(requestCtx, message, requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+	}
+}
+
+// keyResponse converts a signing key into its response payload, exposing only its public key.
+func keyResponse(key *internal.SigningKey) (models.SigningKeyResponse, error) {
+	publicKey, err := key.PublicKeyPEM()
+	if err != nil {
+		return models.SigningKeyResponse{}, err
+	}
+
+	return models.SigningKeyResponse{
+		Name:      key.Name,
+		Type:      string(key.Type),
+		PublicKey: publicKey,
+		CreatedAt: key.CreatedAt,
+	}, nil
+}
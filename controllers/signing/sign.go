@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"go-secrets/errors"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Sign data
+// @Description Signs base64 encoded data with a signing key. Ed25519 keys sign the data itself, ECDSA P-256 keys sign its SHA-256 digest and return an ASN.1 DER signature. Requires a token with a policy granting write on the path.
+// @Tags signing
+// @Accept json
+// @Produce json
+// @Param name path string true "Key name"
+// @Param body body models.SignRequest true "Data to sign"
+// @Security BearerAuth
+// @Success 200 {object} models.SignResponse "Signature"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Token without a policy granting the key"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /signing/sign/{name} [post]
+func (sc *SigningControllerImpl) Sign(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var req models.SignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		sc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	input, err := base64.StdEncoding.DecodeString(req.Input)
+	if err != nil {
+		sc.Logger.LogWarn(requestCtx, "input is not base64 encoded", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	signature, err := sc.Signing.Sign(requestCtx, ctx.Param("name"), input)
+	if err != nil {
+		sc.respondError(ctx, "signing failed", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.SignResponse{Signature: base64.StdEncoding.EncodeToString(signature)})
+}
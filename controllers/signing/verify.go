@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"go-secrets/errors"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Verify a signature
+// @Description Verifies a base64 encoded signature of base64 encoded data with a signing key
+// @Tags signing
+// @Accept json
+// @Produce json
+// @Param name path string true "Key name"
+// @Param body body models.VerifyRequest true "Data and signature"
+// @Security BearerAuth
+// @Success 200 {object} models.VerifyResponse "Verification result"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /signing/verify/{name} [post]
+func (sc *SigningControllerImpl) Verify(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var req models.VerifyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		sc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	input, err := base64.StdEncoding.DecodeString(req.Input)
+	if err != nil {
+		sc.Logger.LogWarn(requestCtx, "input is not base64 encoded", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(req.Signature)
+	if err != nil {
+		sc.Logger.LogWarn(requestCtx, "signature is not base64 encoded", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	valid, err := sc.Signing.Verify(requestCtx, ctx.Param("name"), input, signature)
+	if err != nil {
+		sc.respondError(ctx, "signature verification failed", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.VerifyResponse{Valid: valid})
+}
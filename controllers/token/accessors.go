@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List token accessors
+// @Description Lists the accessors of all tokens. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TokenAccessorsResponse "Token accessors"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/tokens [get]
+func (tc *TokenControllerImpl) ListAccessors(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	accessors, err := tc.Token.ListAccessors(requestCtx, tc.Redis)
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to list token accessors", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	slices.Sort(accessors)
+	ctx.JSON(http.StatusOK, models.TokenAccessorsResponse{Accessors: accessors})
+}
+
+// @Summary Look up a token by accessor
+// @Description Returns the metadata, remaining TTL, usage and number of secrets of the token with the given accessor. Requires the root token.
+// @Tags sys
+// @Produce json
+// @Param accessor path string true "Token accessor"
+// @Security BearerAuth
+// @Success 200 {object} models.TokenLookupResponse "Token metadata"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "Token not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/tokens/{accessor} [get]
+func (tc *TokenControllerImpl) LookupAccessor(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	record, err := tc.Token.LookupAccessor(requestCtx, ctx.Param("accessor"), tc.Redis)
+	if stderrors.Is(err, internal.ErrTokenNotFound) {
+		tc.Logger.LogWarn(requestCtx, "token not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to look up token accessor", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	response, err := tc.describe(requestCtx, record)
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to describe token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// @Summary Revoke a token by accessor
+// @Description Revokes the token with the given accessor along with its child tokens, and deletes their secrets, without needing the token itself. Requires the root token.
+// @Tags sys
+// @Param accessor path string true "Token accessor"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Not a root token"
+// @Failure 404 {object} models.ErrorResponse "Token not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /sys/tokens/{accessor} [delete]
+func (tc *TokenControllerImpl) RevokeAccessor(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	record, err := tc.Token.LookupAccessor(requestCtx, ctx.Param("accessor"), tc.Redis)
+	if stderrors.Is(err, internal.ErrTokenNotFound) {
+		tc.Logger.LogWarn(requestCtx, "token not found", requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+		return
+	} else if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to look up token accessor", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	if err := tc.Token.RevokeToken(requestCtx, record, tc.Redis); err != nil {
+		tc.Logger.LogError(requestCtx, "failed to revoke token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
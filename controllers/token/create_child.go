@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/helpers"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create a child token
+// @Description Creates a token on behalf of the authenticated token, with equal or narrower policies and a TTL, max TTL and number of uses no greater than its own. The child is revoked along with its parent unless it is an orphan.
+// @Tags token
+// @Accept json
+// @Produce json
+// @Param body body models.CreateChildTokenRequest false "Child token options"
+// @Security BearerAuth
+// @Success 200 {object} models.IssueTokenResponse "Generated child token"
+// @Failure 400 {object} models.ErrorResponse "Invalid TTL, metadata or unknown policy"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Policies or uses not held by the parent"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /token/child [post]
+func (tc *TokenControllerImpl) CreateChild(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var request models.CreateChildTokenRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			tc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+			errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+
+	parent, err := tc.Token.AuthTokenRecord(ctx, tc.Redis)
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to get token record", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	parentTTL, err := tc.Redis.TTL(requestCtx, parent.HMAC)
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to get token TTL", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	remaining := int(parentTTL.Seconds())
+	ttl := min(DefaultTTL, remaining)
+	if request.TTL != 0 {
+		ttl = request.TTL
+	}
+	if ttl <= 0 || ttl > remaining {
+		tc.Logger.LogWarn(requestCtx, "invalid child token ttl", requestID, nil)
+		errors.ErrChildTokenTTL.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	// A child cannot be renewed past the point its parent can, tokens without a max TTL cannot be renewed at all
+	remainingMax := remaining
+	if maxExpiry := parent.MaxExpiry(); !maxExpiry.IsZero() {
+		remainingMax = max(remaining, int(time.Until(maxExpiry).Seconds()))
+	}
+	maxTTL := remainingMax
+	if request.MaxTTL != 0 {
+		maxTTL = request.MaxTTL
+	}
+	if maxTTL < ttl || maxTTL > remainingMax {
+		tc.Logger.LogWarn(requestCtx, "invalid child token max ttl", requestID, nil)
+		errors.ErrChildTokenTTL.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	// A child of a use-limited token cannot make more requests than its parent has left
+	numUses := request.NumUses
+	if parent.NumUses > 0 {
+		parentUses := ctx.GetInt("token_remaining_uses")
+		if numUses == 0 {
+			numUses = parentUses
+		}
+		if numUses == 0 || numUses > parentUses {
+			tc.Logger.LogWarn(requestCtx, "child token uses exceed parent", requestID, nil)
+			errors.ErrChildTokenUses.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+	if numUses < 0 {
+		tc.Logger.LogWarn(requestCtx, "invalid child token uses", requestID, nil)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	policies := request.Policies
+	if len(policies) == 0 {
+		policies = parent.Policies
+	}
+	// A parent without policies can only create children without policies, which are kept to their own namespace
+	for _, name := range policies {
+		if !slices.Contains(parent.Policies, name) {
+			tc.Logger.LogWarn(requestCtx, "child token policy not held by parent", requestID, nil)
+			errors.ErrChildTokenPolicies.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+		if _, err := tc.Policies.Get(requestCtx, name); stderrors.Is(err, internal.ErrPolicyNotFound) {
+			tc.Logger.LogWarn(requestCtx, "policy not found", requestID, err)
+			errors.ErrPolicyNotFound.WithRequestID(ctx).JSON(ctx)
+			return
+		} else if err != nil {
+			tc.Logger.LogError(requestCtx, "failed to get policy", requestID, err)
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+
+	if err := helpers.ValidateTokenMetadata(request.DisplayName, request.Labels); err != nil {
+		tc.Logger.LogWarn(requestCtx, "invalid token metadata", requestID, err)
+		errors.ErrInvalidTokenMetadata.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	token, err := tc.Token.GenerateToken()
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to generate token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	record := internal.TokenRecord{
+		DisplayName: request.DisplayName,
+		Labels:      request.Labels,
+		Policies:    policies,
+		CreatedAt:   time.Now(),
+		CreatorIP:   ctx.ClientIP(),
+		TTL:         ttl,
+		MaxTTL:      maxTTL,
+		NumUses:     numUses,
+	}
+	if !request.Orphan {
+		record.Parent = parent.Namespace
+	}
+	stored, err := tc.Token.StoreToken(requestCtx, token, time.Duration(ttl)*time.Second, record, tc.Redis)
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to store token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	response := models.IssueTokenResponse{
+		Token:    token,
+		Accessor: stored.Accessor,
+		TTL:      ttl,
+		MaxTTL:   maxTTL,
+		NumUses:  numUses,
+		Policies: policies,
+		Orphan:   request.Orphan,
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"go-secrets/helpers"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parentTokens authenticates every request as the parent token and records the child token stored last.
+type parentTokens struct {
+	internal.TokenService
+	parent *internal.TokenRecord
+	stored *internal.TokenRecord
+}
+
+func (p *parentTokens) AuthTokenRecord(ctx *gin.Context, r internal.RedisService) (*internal.TokenRecord, error) {
+	return p.parent, nil
+}
+
+func (p *parentTokens) GenerateToken(byteLength ...int) (string, error) {
+	return "child-token", nil
+}
+
+func (p *parentTokens) StoreToken(ctx context.Context, token string, ttl time.Duration, record internal.TokenRecord, r internal.RedisService) (*internal.TokenRecord, error) {
+	record.Accessor = "child-accessor"
+	p.stored = &record
+	return &record, nil
+}
+
+// parentTTL reports the same remaining TTL for every key.
+type parentTTL struct {
+	internal.RedisService
+	ttl time.Duration
+}
+
+func (p *parentTTL) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return p.ttl, nil
+}
+
+// knownPolicies holds empty policies with the given names.
+type knownPolicies struct {
+	internal.PolicyService
+	names []string
+}
+
+func (k *knownPolicies) Get(ctx context.Context, name string) (helpers.Policy, error) {
+	if !slices.Contains(k.names, name) {
+		return helpers.Policy{}, internal.ErrPolicyNotFound
+	}
+	return helpers.Policy{}, nil
+}
+
+func TestCreateChildBounds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		expected models.IssueTokenResponse
+	}{
+		{
+			name:     "defaults to the parent's remaining bounds",
+			status:   http.StatusOK,
+			expected: models.IssueTokenResponse{TTL: 600, MaxTTL: 3600, NumUses: 3, Policies: []string{"ci", "deploy"}},
+		},
+		{
+			name:     "accepts narrower bounds",
+			body:     `{"ttl":60,"max_ttl":120,"num_uses":2,"policies":["ci"]}`,
+			status:   http.StatusOK,
+			expected: models.IssueTokenResponse{TTL: 60, MaxTTL: 120, NumUses: 2, Policies: []string{"ci"}},
+		},
+		{name: "rejects a TTL past the parent's", body: `{"ttl":601}`, status: http.StatusBadRequest},
+		{name: "rejects a max TTL below the TTL", body: `{"ttl":60,"max_ttl":30}`, status: http.StatusBadRequest},
+		{name: "rejects a max TTL past the parent's", body: `{"max_ttl":3700}`, status: http.StatusBadRequest},
+		{name: "rejects more uses than the parent has left", body: `{"num_uses":4}`, status: http.StatusForbidden},
+		{name: "rejects policies the parent does not have", body: `{"policies":["admin"]}`, status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := &internal.TokenRecord{
+				Namespace: "parent",
+				Policies:  []string{"ci", "deploy"},
+				CreatedAt: time.Now(),
+				MaxTTL:    3600,
+				NumUses:   5,
+			}
+			tokens := &parentTokens{parent: parent}
+			controller := &TokenControllerImpl{
+				Logger:   internal.NewLogger(slog.LevelError, io.Discard),
+				Redis:    &parentTTL{ttl: 600 * time.Second},
+				Token:    tokens,
+				Policies: &knownPolicies{names: []string{"ci", "deploy", "admin"}},
+			}
+
+			router := gin.New()
+			router.POST("/token/child", func(ctx *gin.Context) {
+				ctx.Set("token_remaining_uses", 3)
+			}, controller.CreateChild)
+
+			request := httptest.NewRequest(http.MethodPost, "/token/child", strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			require.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			if tt.status != http.StatusOK {
+				assert.Nil(t, tokens.stored)
+				return
+			}
+
+			var response models.IssueTokenResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, tt.expected.TTL, response.TTL)
+			assert.InDelta(t, tt.expected.MaxTTL, response.MaxTTL, 1)
+			assert.Equal(t, tt.expected.NumUses, response.NumUses)
+			assert.Equal(t, tt.expected.Policies, response.Policies)
+			assert.Equal(t, "parent", tokens.stored.Parent)
+		})
+	}
+
+	t.Run("does not link orphans to the parent", func(t *testing.T) {
+		tokens := &parentTokens{parent: &internal.TokenRecord{Namespace: "parent"}}
+		controller := &TokenControllerImpl{
+			Logger:   internal.NewLogger(slog.LevelError, io.Discard),
+			Redis:    &parentTTL{ttl: 600 * time.Second},
+			Token:    tokens,
+			Policies: &knownPolicies{},
+		}
+		router := gin.New()
+		router.POST("/token/child", controller.CreateChild)
+
+		request := httptest.NewRequest(http.MethodPost, "/token/child", strings.NewReader(`{"orphan":true}`))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, tokens.stored.Parent)
+	})
+
+	t.Run("rejects policies for children of a parent without policies", func(t *testing.T) {
+		tokens := &parentTokens{parent: &internal.TokenRecord{Namespace: "parent"}}
+		controller := &TokenControllerImpl{
+			Logger:   internal.NewLogger(slog.LevelError, io.Discard),
+			Redis:    &parentTTL{ttl: 600 * time.Second},
+			Token:    tokens,
+			Policies: &knownPolicies{names: []string{"admin"}},
+		}
+		router := gin.New()
+		router.POST("/token/child", controller.CreateChild)
+
+		request := httptest.NewRequest(http.MethodPost, "/token/child", strings.NewReader(`{"policies":["admin"]}`))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.Nil(t, tokens.stored)
+	})
+}
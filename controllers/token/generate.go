@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"go-secrets/errors"
+	"go-secrets/helpers"
+	"go-secrets/internal"
 	"go-secrets/models"
 	"net/http"
 	"strconv"
@@ -10,21 +12,35 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-const DefaultTTL = 900 // Default TTL of 15 minutes
-const MaxTTL = 3600    // Max TTL of 60 minutes
+const DefaultTTL = 900            // Default TTL of 15 minutes
+const MaxTTL = 3600               // Max TTL of 60 minutes
+const DefaultMaxTTL = 3600        // Default TTL of 60 minutes a token can be renewed up to
+const MaxRenewableTTL = 24 * 3600 // Max TTL of 24 hours a token can be renewed up to
 
 // @Summary Generate a token
-// @Description Generates a short-lived token for secret operations
+// @Description Generates a short-lived token for secret operations, unless anonymous tokens are disabled. Anonymous tokens have full rights over their own namespace and no policies, so they cannot use transit or signing keys; tokens with policies are issued by logging in with an auth method or as child tokens.
 // @Tags token
 // @Produce json
 // @Param ttl query int false "Token TTL in seconds"
+// @Param max_ttl query int false "TTL in seconds from creation the token can be renewed up to"
+// @Param num_uses query int false "Number of requests the token can authenticate before it is revoked, 0 for unlimited"
+// @Param display_name query string false "Human readable name of the token"
+// @Param labels query string false "Labels to attach, as labels[key]=value"
 // @Success 200 {object} models.IssueTokenResponse "Generated token"
-// @Failure 400 {object} models.ErrorResponse "Invalid TTL"
+// @Failure 400 {object} models.ErrorResponse "Invalid TTL, max TTL, number of uses or metadata"
+// @Failure 403 {object} models.ErrorResponse "Anonymous tokens are disabled or policies were requested"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /token [get]
 func (tc *TokenControllerImpl) Generate(ctx *gin.Context) {
 	requestCtx := ctx.Request.Context()
 	requestID := ctx.GetString("request_id")
+
+	if !tc.AnonymousTokens {
+		tc.Logger.LogWarn(requestCtx, "anonymous tokens are disabled", requestID, nil)
+		errors.ErrAnonymousTokensDisabled.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
 	ttlStr := ctx.Query("ttl")
 	ttl := DefaultTTL
 
@@ -38,6 +54,43 @@ func (tc *TokenControllerImpl) Generate(ctx *gin.Context) {
 		ttl = parsedTTL
 	}
 
+	maxTTL := max(DefaultMaxTTL, ttl)
+	if maxTTLStr := ctx.Query("max_ttl"); maxTTLStr != "" {
+		parsedMaxTTL, err := strconv.Atoi(maxTTLStr)
+		if err != nil || parsedMaxTTL < ttl || parsedMaxTTL > MaxRenewableTTL {
+			tc.Logger.LogError(requestCtx, "invalid max ttl value", requestID, err)
+			errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+		maxTTL = parsedMaxTTL
+	}
+
+	numUses := 0
+	if numUsesStr := ctx.Query("num_uses"); numUsesStr != "" {
+		parsedNumUses, err := strconv.Atoi(numUsesStr)
+		if err != nil || parsedNumUses < 0 {
+			tc.Logger.LogError(requestCtx, "invalid num_uses value", requestID, err)
+			errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+		numUses = parsedNumUses
+	}
+
+	// Anyone can request an anonymous token, so letting them pick its policies would grant every policy to everyone
+	if _, requested := ctx.GetQueryArray("policy"); requested {
+		tc.Logger.LogWarn(requestCtx, "anonymous token requested with policies", requestID, nil)
+		errors.ErrAnonymousTokenPolicies.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	displayName := ctx.Query("display_name")
+	labels := ctx.QueryMap("labels")
+	if err := helpers.ValidateTokenMetadata(displayName, labels); err != nil {
+		tc.Logger.LogWarn(requestCtx, "invalid token metadata", requestID, err)
+		errors.ErrInvalidTokenMetadata.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
 	token, err := tc.Token.GenerateToken()
 	if err != nil {
 		tc.Logger.LogError(requestCtx, "failed to generate token", requestID, err)
@@ -45,22 +98,31 @@ func (tc *TokenControllerImpl) Generate(ctx *gin.Context) {
 		return
 	}
 
-	tokenHMAC, err := tc.Crypto.GenerateHMAC(token)
-	if err != nil {
-		tc.Logger.LogError(requestCtx, "failed to generate hmac", requestID, err)
-		return
+	record := internal.TokenRecord{
+		DisplayName: displayName,
+		Labels:      labels,
+		CreatedAt:   time.Now(),
+		CreatorIP:   ctx.ClientIP(),
+		TTL:         ttl,
+		MaxTTL:      maxTTL,
+		NumUses:     numUses,
 	}
-
-	err = tc.Redis.Set(requestCtx, tokenHMAC, "1", time.Duration(ttl)*time.Second)
+	stored, err := tc.Token.StoreToken(requestCtx, token, time.Duration(ttl)*time.Second, record, tc.Redis)
 	if err != nil {
 		tc.Logger.LogError(requestCtx, "failed to store token", requestID, err)
 		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
 		return
 	}
 
+	// Record the issued token's accessor in the request log
+	ctx.Set("token_accessor", stored.Accessor)
+
 	response := models.IssueTokenResponse{
-		Token: token,
-		TTL:   ttl,
+		Token:    token,
+		Accessor: stored.Accessor,
+		TTL:      ttl,
+		MaxTTL:   maxTTL,
+		NumUses:  numUses,
 	}
 
 	ctx.JSON(http.StatusOK, response)
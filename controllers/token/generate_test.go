@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateWithoutPolicies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{name: "issues tokens without policies", status: http.StatusOK},
+		{name: "rejects a requested policy", query: "?policy=admin", status: http.StatusForbidden},
+		{name: "rejects several requested policies", query: "?ttl=60&policy=ci&policy=admin", status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &parentTokens{}
+			controller := &TokenControllerImpl{
+				Logger:          internal.NewLogger(slog.LevelError, io.Discard),
+				Token:           tokens,
+				Policies:        &knownPolicies{names: []string{"ci", "admin"}},
+				AnonymousTokens: true,
+			}
+			router := gin.New()
+			router.GET("/token", controller.Generate)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/token"+tt.query, nil))
+
+			require.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			if tt.status != http.StatusOK {
+				assert.Nil(t, tokens.stored)
+				return
+			}
+
+			var response models.IssueTokenResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Empty(t, response.Policies)
+			assert.Empty(t, tokens.stored.Policies)
+		})
+	}
+}
@@ -8,13 +8,22 @@ import (
 
 type TokenController interface {
 	Generate(ctx *gin.Context)
+	CreateChild(ctx *gin.Context)
+	Renew(ctx *gin.Context)
 	Validate(ctx *gin.Context)
+	Self(ctx *gin.Context)
 	Delete(ctx *gin.Context)
+	ListAccessors(ctx *gin.Context)
+	LookupAccessor(ctx *gin.Context)
+	RevokeAccessor(ctx *gin.Context)
 }
 
 type TokenControllerImpl struct {
-	Logger internal.LoggerService
-	Crypto internal.CryptoService
-	Redis  internal.RedisService
-	Token  internal.TokenService
+	Logger   internal.LoggerService
+	Crypto   internal.CryptoService
+	Redis    internal.RedisService
+	Token    internal.TokenService
+	Policies internal.PolicyService
+	// AnonymousTokens allows GET /token to issue tokens without authentication
+	AnonymousTokens bool
 }
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"go-secrets/errors"
+	"go-secrets/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Renew a token
+// @Description Extends the authenticated token's TTL to the increment from now, bounded by the max TTL it was issued with, along with the TTLs of all its secrets. The TTL is never shortened.
+// @Tags token
+// @Accept json
+// @Produce json
+// @Param body body models.RenewTokenRequest false "Renewal options"
+// @Security BearerAuth
+// @Success 200 {object} models.RenewTokenResponse "Token renewed"
+// @Failure 400 {object} models.ErrorResponse "Invalid increment"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /token/renew [post]
+func (tc *TokenControllerImpl) Renew(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	request := models.RenewTokenRequest{Increment: DefaultTTL}
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			tc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+			errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+			return
+		}
+	}
+	if request.Increment <= 0 || request.Increment > MaxRenewableTTL {
+		tc.Logger.LogWarn(requestCtx, "invalid renewal increment", requestID, nil)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	record, err := tc.Token.AuthTokenRecord(ctx, tc.Redis)
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to get token record", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ttl, err := tc.Token.RenewToken(requestCtx, record, time.Duration(request.Increment)*time.Second, tc.Redis)
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to renew token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.RenewTokenResponse{TTL: int(ttl.Seconds())})
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Look up the token
+// @Description Returns the authenticated token's metadata, remaining TTL, usage and number of secrets it holds. Use-limited tokens report their remaining uses, the lookup consumes one.
+// @Tags token
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TokenLookupResponse "Token metadata"
+// @Failure 401 {object} models.ErrorResponse "Invalid or expired token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /token/self [get]
+func (tc *TokenControllerImpl) Self(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	record, err := tc.Token.AuthTokenRecord(ctx, tc.Redis)
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to get token record", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	response, err := tc.describe(requestCtx, record)
+	if err != nil {
+		tc.Logger.LogError(requestCtx, "failed to describe token", requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// describe builds the lookup response of a token record from its metadata, TTL, counters and secrets.
+func (tc *TokenControllerImpl) describe(ctx context.Context, record *internal.TokenRecord) (*models.TokenLookupResponse, error) {
+	ttl, err := tc.Redis.TTL(ctx, record.HMAC)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := tc.Token.RequestCount(ctx, record, tc.Redis)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := tc.Redis.NewScanner(ctx, record.Namespace+":secret:*")
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := 0
+	for iter.Next(ctx) {
+		secrets++
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	response := &models.TokenLookupResponse{
+		Accessor:    record.Accessor,
+		DisplayName: record.DisplayName,
+		Labels:      record.Labels,
+		Policies:    record.Policies,
+		CreatorIP:   record.CreatorIP,
+		OriginalTTL: record.TTL,
+		MaxTTL:      record.MaxTTL,
+		NumUses:     record.NumUses,
+		Requests:    requests,
+		Secrets:     secrets,
+		Child:       record.Parent != "",
+		Root:        record.Root,
+	}
+	if !record.CreatedAt.IsZero() {
+		response.CreatedAt = &record.CreatedAt
+	}
+	if ttl != internal.NoExpiry {
+		expiresAt := time.Now().Add(ttl).UTC().Truncate(time.Second)
+		response.TTL = int(ttl.Seconds())
+		response.ExpiresAt = &expiresAt
+	}
+	if record.NumUses > 0 {
+		remainingUses, err := tc.Token.RemainingUses(ctx, record, tc.Redis)
+		if err != nil {
+			return nil, err
+		}
+		response.RemainingUses = &remainingUses
+	}
+	return response, nil
+}
@@ -8,7 +8,7 @@ import (
 )
 
 // @Summary Validate a token
-// @Description Validates if a token is still active
+// @Description Validates if a token is still active. Use-limited tokens report their remaining uses, the validation consumes one.
 // @Tags token
 // @Security BearerAuth
 // @Success 200 {object} models.TokenValidationResponse "Token is valid"
@@ -19,5 +19,9 @@ func (tc *TokenControllerImpl) Validate(ctx *gin.Context) {
 	response := models.TokenValidationResponse{
 		Valid: true,
 	}
+	if remainingUses, ok := ctx.Get("token_remaining_uses"); ok {
+		uses := remainingUses.(int)
+		response.RemainingUses = &uses
+	}
 	ctx.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"go-secrets/errors"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Configure a transit key
+// @Description Sets the minimum key version allowed to decrypt data. Requires the root token.
+// @Tags transit
+// @Accept json
+// @Produce json
+// @Param name path string true "Key name"
+// @Param body body models.TransitKeyConfigRequest true "Key configuration"
+// @Security BearerAuth
+// @Success 200 {object} models.TransitKeyResponse "Key after configuration"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /transit/keys/{name}/config [post]
+func (tc *TransitControllerImpl) ConfigureKey(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var req models.TransitKeyConfigRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	key, err := tc.Transit.SetMinDecryptionVersion(requestCtx, ctx.Param("name"), req.MinDecryptionVersion)
+	if err != nil {
+		tc.respondError(ctx, "failed to configure transit key", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keyResponse(key))
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create a transit key
+// @Description Creates a named encryption key that never leaves the server. Requires the root token.
+// @Tags transit
+// @Produce json
+// @Param name path string true "Key name"
+// @Security BearerAuth
+// @Success 201 {object} models.TransitKeyResponse "Key created"
+// @Failure 400 {object} models.ErrorResponse "Invalid key name"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 409 {object} models.ErrorResponse "Key already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /transit/keys/{name} [post]
+func (tc *TransitControllerImpl) CreateKey(ctx *gin.Context) {
+	key, err := tc.Transit.CreateKey(ctx.Request.Context(), ctx.Param("name"))
+	if err != nil {
+		tc.respondError(ctx, "failed to create transit key", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, keyResponse(key))
+}
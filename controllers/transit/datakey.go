@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"go-secrets/errors"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Generate a data key
+// @Description Generates a random 256-bit data key encrypted with a transit key. With type `plaintext` the key is also returned in plaintext, with type `wrapped` only its ciphertext is returned. Requires a token with a policy granting write on the path.
+// @Tags transit
+// @Produce json
+// @Param type path string true "Response type" Enums(plaintext, wrapped)
+// @Param name path string true "Key name"
+// @Security BearerAuth
+// @Success 200 {object} models.TransitDataKeyResponse "Data key"
+// @Failure 400 {object} models.ErrorResponse "Invalid type"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Token without a policy granting the key"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /transit/datakey/{type}/{name} [post]
+func (tc *TransitControllerImpl) DataKey(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	keyType := ctx.Param("type")
+	if keyType != "plaintext" && keyType != "wrapped" {
+		tc.Logger.LogWarn(requestCtx, "invalid data key type", requestID, nil)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	dataKey, ciphertext, err := tc.Transit.GenerateDataKey(requestCtx, ctx.Param("name"))
+	if err != nil {
+		tc.respondError(ctx, "failed to generate data key", err)
+		return
+	}
+	defer clear(dataKey)
+
+	response := models.TransitDataKeyResponse{Ciphertext: ciphertext}
+	if keyType == "plaintext" {
+		response.Plaintext = base64.StdEncoding.EncodeToString(dataKey)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"go-secrets/errors"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Decrypt data with a transit key
+// @Description Decrypts a transit ciphertext and returns the base64 encoded data. Requires a token with a policy granting write on the path.
+// @Tags transit
+// @Accept json
+// @Produce json
+// @Param name path string true "Key name"
+// @Param body body models.TransitCiphertextRequest true "Data to decrypt"
+// @Security BearerAuth
+// @Success 200 {object} models.TransitPlaintextResponse "Decrypted data"
+// @Failure 400 {object} models.ErrorResponse "Invalid ciphertext or key version below the minimum decryption version"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Token without a policy granting the key"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /transit/decrypt/{name} [post]
+func (tc *TransitControllerImpl) Decrypt(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var req models.TransitCiphertextRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	plaintext, err := tc.Transit.Decrypt(requestCtx, ctx.Param("name"), req.Ciphertext)
+	if err != nil {
+		tc.respondError(ctx, "transit decryption failed", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.TransitPlaintextResponse{Plaintext: base64.StdEncoding.EncodeToString(plaintext)})
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"go-secrets/errors"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Encrypt data with a transit key
+// @Description Encrypts base64 encoded data with the latest version of a transit key. The data is not stored. Requires a token with a policy granting write on the path.
+// @Tags transit
+// @Accept json
+// @Produce json
+// @Param name path string true "Key name"
+// @Param body body models.TransitEncryptRequest true "Data to encrypt"
+// @Security BearerAuth
+// @Success 200 {object} models.TransitCiphertextResponse "Encrypted data"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Token without a policy granting the key"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /transit/encrypt/{name} [post]
+func (tc *TransitControllerImpl) Encrypt(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var req models.TransitEncryptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(req.Plaintext)
+	if err != nil {
+		tc.Logger.LogWarn(requestCtx, "plaintext is not base64 encoded", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ciphertext, err := tc.Transit.Encrypt(requestCtx, ctx.Param("name"), plaintext)
+	if err != nil {
+		tc.respondError(ctx, "transit encryption failed", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.TransitCiphertextResponse{Ciphertext: ciphertext})
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a transit key
+// @Description Returns the versions and configuration of a transit key, without its key material. Requires the root token.
+// @Tags transit
+// @Produce json
+// @Param name path string true "Key name"
+// @Security BearerAuth
+// @Success 200 {object} models.TransitKeyResponse "Key"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /transit/keys/{name} [get]
+func (tc *TransitControllerImpl) GetKey(ctx *gin.Context) {
+	key, err := tc.Transit.Key(ctx.Request.Context(), ctx.Param("name"))
+	if err != nil {
+		tc.respondError(ctx, "failed to get transit key", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keyResponse(key))
+}
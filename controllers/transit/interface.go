@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	stderrors "errors"
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"go-secrets/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransitController interface {
+	CreateKey(ctx *gin.Context)
+	GetKey(ctx *gin.Context)
+	RotateKey(ctx *gin.Context)
+	ConfigureKey(ctx *gin.Context)
+	Encrypt(ctx *gin.Context)
+	Decrypt(ctx *gin.Context)
+	Rewrap(ctx *gin.Context)
+	DataKey(ctx *gin.Context)
+}
+
+type TransitControllerImpl struct {
+	Logger  internal.LoggerService
+	Transit internal.TransitService
+}
+
+// respondError logs the error of a transit operation and responds with the matching error response.
+func (tc *TransitControllerImpl) respondError(ctx *gin.Context, message string, err error) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	switch {
+	case stderrors.Is(err, internal.ErrTransitKeyNotFound):
+		tc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrNotFound.WithRequestID(ctx).JSON(ctx)
+	case stderrors.Is(err, internal.ErrInvalidKeyName):
+		tc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrInvalidKeyName.WithRequestID(ctx).JSON(ctx)
+	case stderrors.Is(err, internal.ErrTransitKeyExists):
+		tc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrKeyExists.WithRequestID(ctx).JSON(ctx)
+	case stderrors.Is(err, internal.ErrInvalidTransitCiphertext):
+		tc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrInvalidCiphertext.WithRequestID(ctx).JSON(ctx)
+	case stderrors.Is(err, internal.ErrTransitKeyVersionDisabled):
+		tc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrKeyVersionDisabled.WithRequestID(ctx).JSON(ctx)
+	case stderrors.Is(err, internal.ErrInvalidMinDecryptionVersion):
+		tc.Logger.LogWarn(requestCtx, message, requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+	default:
+		tc.Logger.LogError(requestCtx, message, requestID, err)
+		errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+	}
+}
+
+// keyResponse converts a transit key into its response payload, leaving out the key material.
+func keyResponse(key *internal.TransitKey) models.TransitKeyResponse {
+	response := models.TransitKeyResponse{
+		Name:                 key.Name,
+		LatestVersion:        key.LatestVersion,
+		MinDecryptionVersion: key.MinDecryptionVersion,
+		Versions:             make([]models.TransitKeyVersionResponse, 0, len(key.Versions)),
+		CreatedAt:            key.CreatedAt,
+	}
+	for version := 1; version <= key.LatestVersion; version++ {
+		if keyVersion, ok := key.Versions[version]; ok {
+			response.Versions = append(response.Versions, models.TransitKeyVersionResponse{
+				Version:   version,
+				CreatedAt: keyVersion.CreatedAt,
+			})
+		}
+	}
+	return response
+}
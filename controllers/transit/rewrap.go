@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"go-secrets/errors"
+	"go-secrets/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Rewrap data with the latest transit key version
+// @Description Re-encrypts a transit ciphertext with the latest version of its key without revealing the data. Requires a token with a policy granting write on the path.
+// @Tags transit
+// @Accept json
+// @Produce json
+// @Param name path string true "Key name"
+// @Param body body models.TransitCiphertextRequest true "Data to rewrap"
+// @Security BearerAuth
+// @Success 200 {object} models.TransitCiphertextResponse "Rewrapped data"
+// @Failure 400 {object} models.ErrorResponse "Invalid ciphertext or key version below the minimum decryption version"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Token without a policy granting the key"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /transit/rewrap/{name} [post]
+func (tc *TransitControllerImpl) Rewrap(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	requestID := ctx.GetString("request_id")
+
+	var req models.TransitCiphertextRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		tc.Logger.LogWarn(requestCtx, "invalid request format", requestID, err)
+		errors.ErrInvalidRequest.WithRequestID(ctx).JSON(ctx)
+		return
+	}
+
+	ciphertext, err := tc.Transit.Rewrap(requestCtx, ctx.Param("name"), req.Ciphertext)
+	if err != nil {
+		tc.respondError(ctx, "transit rewrap failed", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.TransitCiphertextResponse{Ciphertext: ciphertext})
+}
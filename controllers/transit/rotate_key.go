@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Rotate a transit key
+// @Description Adds a new version to a transit key, which encrypts all data from then on. Requires the root token.
+// @Tags transit
+// @Produce json
+// @Param name path string true "Key name"
+// @Security BearerAuth
+// @Success 200 {object} models.TransitKeyResponse "Key after rotation"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Key not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /transit/keys/{name}/rotate [post]
+func (tc *TransitControllerImpl) RotateKey(ctx *gin.Context) {
+	key, err := tc.Transit.RotateKey(ctx.Request.Context(), ctx.Param("name"))
+	if err != nil {
+		tc.respondError(ctx, "failed to rotate transit key", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keyResponse(key))
+}
@@ -15,36 +15,73 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/secret/{key}": {
+        "/admin/config": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Gets a secret by key path",
+                "description": "Returns the effective server configuration. Requires a root token. The configuration is read-only, it is set by environment variables at startup.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "secret"
+                    "admin"
                 ],
-                "summary": "Retrieve a secret",
-                "parameters": [
+                "summary": "Get the server configuration",
+                "responses": {
+                    "200": {
+                        "description": "Server configuration",
+                        "schema": {
+                            "$ref": "#/definitions/models.ServerConfigResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stats": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Secret key",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Counts the tokens, secrets, policies, AppRoles and keys in the store. Requires a root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get store statistics",
                 "responses": {
                     "200": {
-                        "description": "Secret retrieved",
+                        "description": "Store statistics",
                         "schema": {
-                            "$ref": "#/definitions/models.GetSecretResponse"
+                            "$ref": "#/definitions/models.StoreStatsResponse"
                         }
                     },
-                    "400": {
-                        "description": "Missing key path",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -56,14 +93,63 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/approle/login": {
             "post": {
-                "security": [
+                "description": "Exchanges a role ID and secret ID for a token with the AppRole's policies, TTL, max TTL and use limit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log in with an AppRole",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "description": "Role ID and secret ID",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AppRoleLoginRequest"
+                        }
                     }
                 ],
-                "description": "Stores a secret with a key path",
+                "responses": {
+                    "200": {
+                        "description": "Issued token",
+                        "schema": {
+                            "$ref": "#/definitions/models.IssueTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid role ID or secret ID, or not allowed from this address",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/cert/login": {
+            "post": {
+                "description": "Exchanges the client certificate of the TLS connection, verified against the configured CA bundle, for a token with the policies, TTL, max TTL and use limit of the named role, or of the first role by name the certificate matches.",
                 "consumes": [
                     "application/json"
                 ],
@@ -71,36 +157,86 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "secret"
+                    "auth"
                 ],
-                "summary": "Store a secret",
+                "summary": "Log in with a client certificate",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Secret key",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
+                        "description": "Role name",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CertLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Issued token",
+                        "schema": {
+                            "$ref": "#/definitions/models.IssueTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing client certificate or not allowed to log in with the role",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/jwt/login": {
+            "post": {
+                "description": "Exchanges a JWT signed by the role's issuer and matching the role's bound audiences, subject and claims for a token with the role's policies, TTL, max TTL and use limit. Mapped claims become token labels.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log in with a JWT",
+                "parameters": [
                     {
-                        "description": "Secret data",
+                        "description": "Role name and JWT",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.StoreSecretRequest"
+                            "$ref": "#/definitions/models.JWTLoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Secret stored",
+                        "description": "Issued token",
                         "schema": {
-                            "$ref": "#/definitions/models.StoreSecretResponse"
+                            "$ref": "#/definitions/models.IssueTokenResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid request or mapped claims that are not valid token metadata",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid JWT or not allowed to log in with the role",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -112,18 +248,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/secret/{key}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a secret by key path",
+                "description": "Gets a secret by key path, or lists the keys under the path when list is true. Values encrypted by the client are returned as stored, flagged as client encrypted. Binary secrets are streamed as the raw response body with their content type, and their TTL in the X-Secret-TTL header.",
+                "produces": [
+                    "application/json",
+                    "application/octet-stream"
+                ],
                 "tags": [
                     "secret"
                 ],
-                "summary": "Delete a secret",
+                "summary": "Retrieve a secret",
                 "parameters": [
                     {
                         "type": "string",
@@ -131,11 +273,20 @@ const docTemplate = `{
                         "name": "key",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List the keys starting with the key path instead",
+                        "name": "list",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Keys listed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ListSecretsResponse"
+                        }
                     },
                     "400": {
                         "description": "Missing key path",
@@ -144,41 +295,65 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error or secret integrity check failed",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/token": {
-            "get": {
-                "description": "Generates a short-lived token for secret operations",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a secret with a key path. Instead of a value, a generate spec or the name of a password policy can be given to generate the value on the server, which is returned once in the response. Values flagged as client encrypted are stored as opaque blobs without server-side encryption. Binary secrets are uploaded as the raw request body with their content type, or as the ` + "`" + `file` + "`" + ` field of a multipart form, and encrypted while streaming.",
+                "consumes": [
+                    "application/json",
+                    "application/octet-stream",
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "token"
+                    "secret"
                 ],
-                "summary": "Generate a token",
+                "summary": "Store a secret",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Token TTL in seconds",
-                        "name": "ttl",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Secret key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Secret data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StoreSecretRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Generated token",
+                        "description": "Secret stored",
                         "schema": {
-                            "$ref": "#/definitions/models.IssueTokenResponse"
+                            "$ref": "#/definitions/models.StoreSecretResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid TTL",
+                        "description": "Invalid request or password policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Secret exceeds the maximum size",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -197,15 +372,30 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes all stored secrets for the authenticated token",
+                "description": "Deletes a secret by key path",
                 "tags": [
-                    "token"
+                    "secret"
+                ],
+                "summary": "Delete a secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Secret key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Delete all secrets for a token",
                 "responses": {
                     "204": {
                         "description": "No Content"
                     },
+                    "400": {
+                        "description": "Missing key path",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -215,27 +405,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/token/valid": {
+        "/signing/keys/{name}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Validates if a token is still active",
+                "description": "Returns the type and PEM encoded public key of a signing key",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "token"
+                    "signing"
+                ],
+                "summary": "Export a public key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Validate a token",
                 "responses": {
                     "200": {
-                        "description": "Token is valid",
+                        "description": "Key",
                         "schema": {
-                            "$ref": "#/definitions/models.TokenValidationResponse"
+                            "$ref": "#/definitions/models.SigningKeyResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid or expired token",
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -247,42 +455,3370 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a named Ed25519 or ECDSA P-256 key whose private key never leaves the server. Requires the root token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "signing"
+                ],
+                "summary": "Create a signing key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Key type",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSigningKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Key created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SigningKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid key name or type",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Key already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/signing/sign/{name}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Signs base64 encoded data with a signing key. Ed25519 keys sign the data itself, ECDSA P-256 keys sign its SHA-256 digest and return an ASN.1 DER signature. Requires a token with a policy granting write on the path.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "signing"
+                ],
+                "summary": "Sign data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data to sign",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Signature",
+                        "schema": {
+                            "$ref": "#/definitions/models.SignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Token without a policy granting the key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/signing/verify/{name}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verifies a base64 encoded signature of base64 encoded data with a signing key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "signing"
+                ],
+                "summary": "Verify a signature",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data and signature",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verification result",
+                        "schema": {
+                            "$ref": "#/definitions/models.VerifyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/approle/roles/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a named AppRole and its role ID. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Get an AppRole",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AppRole name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "AppRole",
+                        "schema": {
+                            "$ref": "#/definitions/models.AppRoleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "AppRole not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a named AppRole that machines log in with to get tokens with its policies, TTL and use limit. An existing role keeps its role ID and secret IDs. Requires the root token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Create or update an AppRole",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AppRole name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "AppRole",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AppRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored AppRole",
+                        "schema": {
+                            "$ref": "#/definitions/models.AppRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid AppRole name, TTLs, use limits, CIDRs or unknown policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a named AppRole and all secret IDs issued for it. Tokens issued through it stay valid until they expire or are revoked. Requires the root token.",
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Delete an AppRole",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AppRole name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "AppRole not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/approle/roles/{name}/secret-id": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues a secret ID for an AppRole, which is only returned once. Its TTL and use limit default to the role's and cannot exceed them, and its CIDRs restrict logins in addition to the role's. Requires the root token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Issue a secret ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AppRole name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Secret ID restrictions",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.IssueSecretIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Issued secret ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.SecretIDResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid TTL, use limit or CIDRs",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "AppRole not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/cert/roles/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a certificate role. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Get a certificate role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Certificate role",
+                        "schema": {
+                            "$ref": "#/definitions/models.CertRoleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Certificate role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a named certificate role that maps verified client certificates matching its allowed common names, organizational units and SANs to tokens with its policies, TTL and use limit. Requires the root token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Create or update a certificate role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Certificate role",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CertRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored certificate role",
+                        "schema": {
+                            "$ref": "#/definitions/models.CertRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role name, TTLs, allowed names or unknown policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a certificate role. Tokens issued by logging in with it stay valid until they expire or are revoked, session tokens of client certificates stop working. Requires the root token.",
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Delete a certificate role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Certificate role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/jwt/issuers/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a trusted JWT issuer. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Get a JWT issuer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issuer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JWT issuer",
+                        "schema": {
+                            "$ref": "#/definitions/models.JWTIssuerResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "JWT issuer not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Configures a trusted JWT issuer and where its key set is loaded from, a JWKS URL or a JWKS file on the server. The key set is loaded once to check it. Requires the root token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Create or update a JWT issuer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issuer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JWT issuer",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.JWTIssuerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored JWT issuer",
+                        "schema": {
+                            "$ref": "#/definitions/models.JWTIssuerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid issuer name, configuration or key set",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a trusted JWT issuer. Roles referencing it can no longer be logged in with. Requires the root token.",
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Delete a JWT issuer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issuer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "JWT issuer not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/jwt/roles/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a JWT role. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Get a JWT role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JWT role",
+                        "schema": {
+                            "$ref": "#/definitions/models.JWTRoleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "JWT role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a named JWT role that maps JWTs of an issuer matching its bound audiences, subject and claims to tokens with its policies, TTL and use limit. Requires the root token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Create or update a JWT role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JWT role",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.JWTRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored JWT role",
+                        "schema": {
+                            "$ref": "#/definitions/models.JWTRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role name, TTLs, claim rules, unknown issuer or policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a JWT role. Tokens issued through it stay valid until they expire or are revoked. Requires the root token.",
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Delete a JWT role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "JWT role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/keyring": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the root key versions and the progress of the rewrap job. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Get keyring status",
+                "responses": {
+                    "200": {
+                        "description": "Keyring status",
+                        "schema": {
+                            "$ref": "#/definitions/models.KeyringStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/keyring/rewrap": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Re-encrypts stored secrets that use an older key version or another algorithm than the configured one. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Start a rewrap job",
+                "responses": {
+                    "202": {
+                        "description": "Rewrap job started",
+                        "schema": {
+                            "$ref": "#/definitions/models.KeyringStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Rewrap job already running",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/keyring/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new root key version and starts re-encrypting stored secrets with it in the background. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Rotate the root key",
+                "responses": {
+                    "200": {
+                        "description": "Keyring status after rotation",
+                        "schema": {
+                            "$ref": "#/definitions/models.KeyringStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Rewrap job already running",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/keyring/{version}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a root key version once no token record or secret references it anymore. Requires the root token.",
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Retire a root key version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Key version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid key version",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key version not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Key version is active or still in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/password-policies/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a named password policy. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Get a password policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password policy",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.PasswordPolicy"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Password policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a named password policy that secrets can reference in their generate spec. Requires the root token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Create or update a password policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Password policy",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/helpers.PasswordPolicy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored password policy",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.PasswordPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid policy name or password policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a named password policy. Requires the root token.",
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Delete a password policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Password policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/policies/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a named token policy. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Get a token policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Policy",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Policy"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a named token policy of path globs and the capabilities granted on them, which tokens can be issued with. Requires the root token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Create or update a token policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Policy",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Policy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored policy",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Policy"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid policy name or policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a named token policy. Tokens it was attached to lose the rights it granted. Requires the root token.",
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Delete a token policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/seal": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Unloads the keyring and wipes the master key from memory. Token and secret routes return 503 until the server is unsealed again. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Seal the server",
+                "responses": {
+                    "200": {
+                        "description": "Seal status",
+                        "schema": {
+                            "$ref": "#/definitions/models.SealStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/seal-status": {
+            "get": {
+                "description": "Reports whether the server is sealed and how many unseal key shares have been submitted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Get seal status",
+                "responses": {
+                    "200": {
+                        "description": "Seal status",
+                        "schema": {
+                            "$ref": "#/definitions/models.SealStatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the accessors of all tokens. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "List token accessors",
+                "responses": {
+                    "200": {
+                        "description": "Token accessors",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenAccessorsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/tokens/{accessor}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the metadata, remaining TTL, usage and number of secrets of the token with the given accessor. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Look up a token by accessor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token accessor",
+                        "name": "accessor",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token metadata",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenLookupResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the token with the given accessor along with its child tokens, and deletes their secrets, without needing the token itself. Requires the root token.",
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Revoke a token by accessor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token accessor",
+                        "name": "accessor",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a root token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sys/unseal": {
+            "post": {
+                "description": "Submits one hex encoded unseal key share, or the master key when the server uses a master key file. The server is unsealed once the threshold of shares is reached. Set reset to discard the shares submitted so far.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sys"
+                ],
+                "summary": "Submit an unseal key",
+                "parameters": [
+                    {
+                        "description": "Unseal key",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UnsealRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Seal status",
+                        "schema": {
+                            "$ref": "#/definitions/models.SealStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid unseal key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Key material does not match the store or internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/token": {
+            "get": {
+                "description": "Generates a short-lived token for secret operations, unless anonymous tokens are disabled. Anonymous tokens have full rights over their own namespace and no policies, so they cannot use transit or signing keys; tokens with policies are issued by logging in with an auth method or as child tokens.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token"
+                ],
+                "summary": "Generate a token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token TTL in seconds",
+                        "name": "ttl",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "TTL in seconds from creation the token can be renewed up to",
+                        "name": "max_ttl",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of requests the token can authenticate before it is revoked, 0 for unlimited",
+                        "name": "num_uses",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Human readable name of the token",
+                        "name": "display_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Labels to attach, as labels[key]=value",
+                        "name": "labels",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Generated token",
+                        "schema": {
+                            "$ref": "#/definitions/models.IssueTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid TTL, max TTL, number of uses or metadata",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Anonymous tokens are disabled or policies were requested",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the authenticated token and deletes all its stored secrets. Child tokens it created are revoked the same way, orphan children are not.",
+                "tags": [
+                    "token"
+                ],
+                "summary": "Delete all secrets for a token",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/child": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a token on behalf of the authenticated token, with equal or narrower policies and a TTL, max TTL and number of uses no greater than its own. The child is revoked along with its parent unless it is an orphan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token"
+                ],
+                "summary": "Create a child token",
+                "parameters": [
+                    {
+                        "description": "Child token options",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateChildTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Generated child token",
+                        "schema": {
+                            "$ref": "#/definitions/models.IssueTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid TTL, metadata or unknown policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Policies or uses not held by the parent",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/renew": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extends the authenticated token's TTL to the increment from now, bounded by the max TTL it was issued with, along with the TTLs of all its secrets. The TTL is never shortened.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token"
+                ],
+                "summary": "Renew a token",
+                "parameters": [
+                    {
+                        "description": "Renewal options",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RenewTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token renewed",
+                        "schema": {
+                            "$ref": "#/definitions/models.RenewTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid increment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/self": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the authenticated token's metadata, remaining TTL, usage and number of secrets it holds. Use-limited tokens report their remaining uses, the lookup consumes one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token"
+                ],
+                "summary": "Look up the token",
+                "responses": {
+                    "200": {
+                        "description": "Token metadata",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenLookupResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/valid": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Validates if a token is still active. Use-limited tokens report their remaining uses, the validation consumes one.",
+                "tags": [
+                    "token"
+                ],
+                "summary": "Validate a token",
+                "responses": {
+                    "200": {
+                        "description": "Token is valid",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transit/datakey/{type}/{name}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a random 256-bit data key encrypted with a transit key. With type ` + "`" + `plaintext` + "`" + ` the key is also returned in plaintext, with type ` + "`" + `wrapped` + "`" + ` only its ciphertext is returned. Requires a token with a policy granting write on the path.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transit"
+                ],
+                "summary": "Generate a data key",
+                "parameters": [
+                    {
+                        "enum": [
+                            "plaintext",
+                            "wrapped"
+                        ],
+                        "type": "string",
+                        "description": "Response type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Data key",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitDataKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid type",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Token without a policy granting the key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transit/decrypt/{name}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Decrypts a transit ciphertext and returns the base64 encoded data. Requires a token with a policy granting write on the path.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transit"
+                ],
+                "summary": "Decrypt data with a transit key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data to decrypt",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitCiphertextRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Decrypted data",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitPlaintextResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ciphertext or key version below the minimum decryption version",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Token without a policy granting the key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transit/encrypt/{name}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Encrypts base64 encoded data with the latest version of a transit key. The data is not stored. Requires a token with a policy granting write on the path.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transit"
+                ],
+                "summary": "Encrypt data with a transit key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data to encrypt",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitEncryptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Encrypted data",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitCiphertextResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Token without a policy granting the key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transit/keys/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the versions and configuration of a transit key, without its key material. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transit"
+                ],
+                "summary": "Get a transit key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Key",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitKeyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a named encryption key that never leaves the server. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transit"
+                ],
+                "summary": "Create a transit key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Key created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid key name",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Key already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transit/keys/{name}/config": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the minimum key version allowed to decrypt data. Requires the root token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transit"
+                ],
+                "summary": "Configure a transit key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Key configuration",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitKeyConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Key after configuration",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transit/keys/{name}/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new version to a transit key, which encrypts all data from then on. Requires the root token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transit"
+                ],
+                "summary": "Rotate a transit key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Key after rotation",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitKeyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transit/rewrap/{name}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Re-encrypts a transit ciphertext with the latest version of its key without revealing the data. Requires a token with a policy granting write on the path.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transit"
+                ],
+                "summary": "Rewrap data with the latest transit key version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data to rewrap",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitCiphertextRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rewrapped data",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransitCiphertextResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ciphertext or key version below the minimum decryption version",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Token without a policy granting the key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "helpers.PasswordPolicy": {
+            "description": "Password policy format",
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "description": "Generate this many random bytes instead of a password",
+                    "type": "integer"
+                },
+                "classes": {
+                    "description": "lowercase, uppercase, digits and/or symbols, defaults to all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "encoding": {
+                    "description": "hex or base64 encoding of random bytes, defaults to hex",
+                    "type": "string"
+                },
+                "exclude": {
+                    "description": "Characters never to use",
+                    "type": "string"
+                },
+                "length": {
+                    "description": "Password length, defaults to 24",
+                    "type": "integer"
+                },
+                "min_counts": {
+                    "description": "Minimum number of characters per class",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "helpers.Policy": {
+            "description": "Token policy format",
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/helpers.PolicyRule"
+                    }
+                }
+            }
+        },
+        "helpers.PolicyRule": {
+            "type": "object",
+            "properties": {
+                "capabilities": {
+                    "description": "read, write, delete and/or list",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "path": {
+                    "description": "Path glob, e.g. secret/ci/*",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AppRoleLoginRequest": {
+            "description": "AppRole login request format",
+            "type": "object",
+            "required": [
+                "role_id",
+                "secret_id"
+            ],
+            "properties": {
+                "role_id": {
+                    "type": "string"
+                },
+                "secret_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AppRoleRequest": {
+            "description": "AppRole request format",
+            "type": "object",
+            "properties": {
+                "policies": {
+                    "description": "Names of the policies attached to issued tokens",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret_id_bound_cidrs": {
+                    "description": "Addresses any secret ID of the role may log in from",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret_id_num_uses": {
+                    "description": "Default and maximum logins per secret ID, 0 for unlimited",
+                    "type": "integer"
+                },
+                "secret_id_ttl": {
+                    "description": "Default and maximum TTL in seconds of secret IDs, 0 for no expiry",
+                    "type": "integer"
+                },
+                "token_max_ttl": {
+                    "description": "TTL in seconds issued tokens can be renewed up to",
+                    "type": "integer"
+                },
+                "token_num_uses": {
+                    "description": "Use limit of issued tokens, 0 for unlimited",
+                    "type": "integer"
+                },
+                "token_ttl": {
+                    "description": "TTL in seconds of issued tokens",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AppRoleResponse": {
+            "description": "AppRole format",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "role_id": {
+                    "type": "string"
+                },
+                "secret_id_bound_cidrs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret_id_num_uses": {
+                    "type": "integer"
+                },
+                "secret_id_ttl": {
+                    "type": "integer"
+                },
+                "token_max_ttl": {
+                    "type": "integer"
+                },
+                "token_num_uses": {
+                    "type": "integer"
+                },
+                "token_ttl": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CertLoginRequest": {
+            "description": "Certificate login request format",
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CertRoleRequest": {
+            "description": "Certificate role request format",
+            "type": "object",
+            "properties": {
+                "allowed_common_names": {
+                    "description": "Allowed subject common names",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowed_dns_sans": {
+                    "description": "Allowed DNS name SANs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowed_email_sans": {
+                    "description": "Allowed email address SANs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowed_organizational_units": {
+                    "description": "Allowed subject organizational units",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowed_uri_sans": {
+                    "description": "Allowed URI SANs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "policies": {
+                    "description": "Names of the policies attached to issued tokens",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_max_ttl": {
+                    "description": "TTL in seconds tokens issued by logging in can be renewed up to",
+                    "type": "integer"
+                },
+                "token_num_uses": {
+                    "description": "Use limit of tokens issued by logging in, 0 for unlimited",
+                    "type": "integer"
+                },
+                "token_ttl": {
+                    "description": "TTL in seconds of issued tokens",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CertRoleResponse": {
+            "description": "Certificate role format",
+            "type": "object",
+            "properties": {
+                "allowed_common_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowed_dns_sans": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowed_email_sans": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowed_organizational_units": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowed_uri_sans": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_max_ttl": {
+                    "type": "integer"
+                },
+                "token_num_uses": {
+                    "type": "integer"
+                },
+                "token_ttl": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateChildTokenRequest": {
+            "description": "Create child token request format",
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "max_ttl": {
+                    "description": "TTL in seconds the child can be renewed up to, at most the parent's remaining max TTL",
+                    "type": "integer"
+                },
+                "num_uses": {
+                    "description": "Use limit, at most the parent's remaining uses if it has a limit",
+                    "type": "integer"
+                },
+                "orphan": {
+                    "description": "The child is not revoked along with its parent",
+                    "type": "boolean"
+                },
+                "policies": {
+                    "description": "Names of policies, a subset of the parent's",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ttl": {
+                    "description": "TTL in seconds, at most the parent's remaining TTL",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateSigningKeyRequest": {
+            "description": "Create signing key request format",
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "type": {
+                    "description": "ed25519 or ecdsa-p256",
+                    "type": "string"
+                }
+            }
+        },
         "models.ErrorResponse": {
             "description": "API error response format",
             "type": "object",
             "properties": {
-                "message": {
+                "message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GenerateSecretSpec": {
+            "description": "Secret generation spec format",
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "description": "Generate this many random bytes instead of a password",
+                    "type": "integer"
+                },
+                "classes": {
+                    "description": "lowercase, uppercase, digits and/or symbols, defaults to all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "encoding": {
+                    "description": "hex or base64 encoding of random bytes, defaults to hex",
+                    "type": "string"
+                },
+                "exclude": {
+                    "description": "Characters never to use",
+                    "type": "string"
+                },
+                "length": {
+                    "description": "Password length, defaults to 24",
+                    "type": "integer"
+                },
+                "min_counts": {
+                    "description": "Minimum number of characters per class",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "policy": {
+                    "description": "Name of a stored password policy",
+                    "type": "string"
+                }
+            }
+        },
+        "models.GetSecretResponse": {
+            "description": "Get secret response format",
+            "type": "object",
+            "properties": {
+                "client_encrypted": {
+                    "type": "boolean"
+                },
+                "ttl": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IssueSecretIDRequest": {
+            "description": "Issue secret ID request format",
+            "type": "object",
+            "properties": {
+                "cidr_list": {
+                    "description": "Addresses the secret ID may log in from, in addition to the role's",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "num_uses": {
+                    "description": "Number of logins before the secret ID is deleted",
+                    "type": "integer"
+                },
+                "ttl": {
+                    "description": "TTL in seconds",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.IssueTokenResponse": {
+            "description": "Issue token response format",
+            "type": "object",
+            "properties": {
+                "accessor": {
+                    "type": "string"
+                },
+                "max_ttl": {
+                    "type": "integer"
+                },
+                "num_uses": {
+                    "type": "integer"
+                },
+                "orphan": {
+                    "type": "boolean"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "type": "string"
+                },
+                "ttl": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.JWTIssuerRequest": {
+            "description": "JWT issuer request format",
+            "type": "object",
+            "required": [
+                "issuer"
+            ],
+            "properties": {
+                "issuer": {
+                    "description": "Expected iss claim",
+                    "type": "string"
+                },
+                "jwks_file": {
+                    "description": "Path of a key set file on the server",
+                    "type": "string"
+                },
+                "jwks_url": {
+                    "description": "URL the key set is fetched from",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JWTIssuerResponse": {
+            "description": "JWT issuer format",
+            "type": "object",
+            "properties": {
+                "issuer": {
+                    "type": "string"
+                },
+                "jwks_file": {
+                    "type": "string"
+                },
+                "jwks_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JWTLoginRequest": {
+            "description": "JWT login request format",
+            "type": "object",
+            "required": [
+                "jwt",
+                "role"
+            ],
+            "properties": {
+                "jwt": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JWTRoleRequest": {
+            "description": "JWT role request format",
+            "type": "object",
+            "required": [
+                "issuer"
+            ],
+            "properties": {
+                "bound_audiences": {
+                    "description": "Required when the JWTs have an aud claim",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "bound_claims": {
+                    "description": "Allowed values of claims, which may contain * globs",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "bound_subject": {
+                    "description": "Required sub claim",
+                    "type": "string"
+                },
+                "claim_mappings": {
+                    "description": "Claims copied into token labels, mapped to the label key",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "issuer": {
+                    "description": "Name of the JWT issuer",
+                    "type": "string"
+                },
+                "policies": {
+                    "description": "Names of the policies attached to issued tokens",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_max_ttl": {
+                    "description": "TTL in seconds issued tokens can be renewed up to",
+                    "type": "integer"
+                },
+                "token_num_uses": {
+                    "description": "Use limit of issued tokens, 0 for unlimited",
+                    "type": "integer"
+                },
+                "token_ttl": {
+                    "description": "TTL in seconds of issued tokens",
+                    "type": "integer"
+                },
+                "user_claim": {
+                    "description": "Claim used as the token's display name, defaults to sub",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JWTRoleResponse": {
+            "description": "JWT role format",
+            "type": "object",
+            "properties": {
+                "bound_audiences": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "bound_claims": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "bound_subject": {
+                    "type": "string"
+                },
+                "claim_mappings": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_max_ttl": {
+                    "type": "integer"
+                },
+                "token_num_uses": {
+                    "type": "integer"
+                },
+                "token_ttl": {
+                    "type": "integer"
+                },
+                "user_claim": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KeyringKeyResponse": {
+            "description": "Keyring key format",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.KeyringStatusResponse": {
+            "description": "Keyring status format",
+            "type": "object",
+            "properties": {
+                "active_version": {
+                    "type": "integer"
+                },
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KeyringKeyResponse"
+                    }
+                },
+                "rewrap": {
+                    "$ref": "#/definitions/models.RewrapStatusResponse"
+                }
+            }
+        },
+        "models.ListSecretsResponse": {
+            "description": "List secrets response format",
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RenewTokenRequest": {
+            "description": "Renew token request format",
+            "type": "object",
+            "properties": {
+                "increment": {
+                    "description": "New TTL in seconds from now, bounded by the token's max TTL",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RenewTokenResponse": {
+            "description": "Renew token response format",
+            "type": "object",
+            "properties": {
+                "ttl": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RewrapStatusResponse": {
+            "description": "Rewrap job status format",
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 },
-                "request_id": {
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
                     "type": "string"
                 },
-                "status": {
+                "pending_tokens": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "reencrypted": {
+                    "type": "integer"
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "scanned": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "target_version": {
                     "type": "integer"
                 }
             }
         },
-        "models.GetSecretResponse": {
-            "description": "Get secret response format",
+        "models.SealStatusResponse": {
+            "description": "Seal status format",
             "type": "object",
             "properties": {
-                "ttl": {
+                "key_fingerprint": {
+                    "type": "string"
+                },
+                "key_version": {
                     "type": "integer"
                 },
-                "value": {
+                "progress": {
+                    "type": "integer"
+                },
+                "sealed": {
+                    "type": "boolean"
+                },
+                "shares": {
+                    "type": "integer"
+                },
+                "threshold": {
+                    "type": "integer"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "models.IssueTokenResponse": {
-            "description": "Issue token response format",
+        "models.SecretIDResponse": {
+            "description": "Secret ID response format",
             "type": "object",
             "properties": {
-                "token": {
+                "cidr_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "num_uses": {
+                    "type": "integer"
+                },
+                "secret_id": {
                     "type": "string"
                 },
                 "ttl": {
@@ -290,13 +3826,88 @@ const docTemplate = `{
                 }
             }
         },
-        "models.StoreSecretRequest": {
-            "description": "Store secret request format",
+        "models.ServerConfigResponse": {
+            "description": "Server configuration format",
+            "type": "object",
+            "properties": {
+                "anonymous_tokens": {
+                    "description": "GET /token issues tokens without authentication",
+                    "type": "boolean"
+                },
+                "client_cert_auth": {
+                    "description": "Client certificates are verified against a CA bundle",
+                    "type": "boolean"
+                },
+                "encryption_algorithm": {
+                    "type": "string"
+                },
+                "max_binary_secret_size": {
+                    "type": "integer"
+                },
+                "max_secret_size": {
+                    "type": "integer"
+                },
+                "seal_type": {
+                    "type": "string"
+                },
+                "tls": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SignRequest": {
+            "description": "Sign request format",
             "type": "object",
             "required": [
-                "value"
+                "input"
             ],
             "properties": {
+                "input": {
+                    "description": "Base64 encoded data",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SignResponse": {
+            "description": "Sign response format",
+            "type": "object",
+            "properties": {
+                "signature": {
+                    "description": "Base64 encoded signature",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SigningKeyResponse": {
+            "description": "Signing key format",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "public_key": {
+                    "description": "PEM encoded PKIX public key",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StoreSecretRequest": {
+            "description": "Store secret request format",
+            "type": "object",
+            "properties": {
+                "client_encrypted": {
+                    "description": "The value was encrypted by the client and is stored as an opaque blob",
+                    "type": "boolean"
+                },
+                "generate": {
+                    "$ref": "#/definitions/models.GenerateSecretSpec"
+                },
                 "value": {
                     "type": "string"
                 }
@@ -306,10 +3917,133 @@ const docTemplate = `{
             "description": "Store secret response format",
             "type": "object",
             "properties": {
+                "content_type": {
+                    "type": "string"
+                },
                 "key": {
                     "type": "string"
                 },
+                "size": {
+                    "type": "integer"
+                },
+                "ttl": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StoreStatsResponse": {
+            "description": "Store statistics format",
+            "type": "object",
+            "properties": {
+                "approles": {
+                    "type": "integer"
+                },
+                "blob_chunks": {
+                    "type": "integer"
+                },
+                "key_version": {
+                    "description": "Active root key version, while unsealed",
+                    "type": "integer"
+                },
+                "keys": {
+                    "type": "integer"
+                },
+                "password_policies": {
+                    "type": "integer"
+                },
+                "policies": {
+                    "type": "integer"
+                },
+                "secrets": {
+                    "type": "integer"
+                },
+                "signing_keys": {
+                    "type": "integer"
+                },
+                "tokens": {
+                    "type": "integer"
+                },
+                "transit_keys": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TokenAccessorsResponse": {
+            "description": "Token accessors response format",
+            "type": "object",
+            "properties": {
+                "accessors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.TokenLookupResponse": {
+            "description": "Token lookup response format",
+            "type": "object",
+            "properties": {
+                "accessor": {
+                    "type": "string"
+                },
+                "child": {
+                    "description": "The token is revoked along with the token that created it",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_ip": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "max_ttl": {
+                    "type": "integer"
+                },
+                "num_uses": {
+                    "type": "integer"
+                },
+                "original_ttl": {
+                    "description": "TTL the token was issued with",
+                    "type": "integer"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remaining_uses": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "description": "Requests the token authenticated, including this one",
+                    "type": "integer"
+                },
+                "root": {
+                    "description": "The token never expires and may only be used for administration",
+                    "type": "boolean"
+                },
+                "secrets": {
+                    "type": "integer"
+                },
                 "ttl": {
+                    "description": "Remaining TTL in seconds, 0 for root tokens",
                     "type": "integer"
                 }
             }
@@ -317,6 +4051,153 @@ const docTemplate = `{
         "models.TokenValidationResponse": {
             "description": "Token validation response format",
             "type": "object",
+            "properties": {
+                "remaining_uses": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.TransitCiphertextRequest": {
+            "description": "Transit ciphertext request format",
+            "type": "object",
+            "required": [
+                "ciphertext"
+            ],
+            "properties": {
+                "ciphertext": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransitCiphertextResponse": {
+            "description": "Transit ciphertext response format",
+            "type": "object",
+            "properties": {
+                "ciphertext": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransitDataKeyResponse": {
+            "description": "Transit data key response format",
+            "type": "object",
+            "properties": {
+                "ciphertext": {
+                    "type": "string"
+                },
+                "plaintext": {
+                    "description": "Base64 encoded data key",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransitEncryptRequest": {
+            "description": "Transit encrypt request format",
+            "type": "object",
+            "required": [
+                "plaintext"
+            ],
+            "properties": {
+                "plaintext": {
+                    "description": "Base64 encoded data",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransitKeyConfigRequest": {
+            "description": "Transit key configuration request format",
+            "type": "object",
+            "required": [
+                "min_decryption_version"
+            ],
+            "properties": {
+                "min_decryption_version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TransitKeyResponse": {
+            "description": "Transit key format",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "latest_version": {
+                    "type": "integer"
+                },
+                "min_decryption_version": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransitKeyVersionResponse"
+                    }
+                }
+            }
+        },
+        "models.TransitKeyVersionResponse": {
+            "description": "Transit key version format",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TransitPlaintextResponse": {
+            "description": "Transit plaintext response format",
+            "type": "object",
+            "properties": {
+                "plaintext": {
+                    "description": "Base64 encoded data",
+                    "type": "string"
+                }
+            }
+        },
+        "models.UnsealRequest": {
+            "description": "Unseal request format",
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "reset": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.VerifyRequest": {
+            "description": "Verify request format",
+            "type": "object",
+            "required": [
+                "input",
+                "signature"
+            ],
+            "properties": {
+                "input": {
+                    "description": "Base64 encoded data",
+                    "type": "string"
+                },
+                "signature": {
+                    "description": "Base64 encoded signature",
+                    "type": "string"
+                }
+            }
+        },
+        "models.VerifyResponse": {
+            "description": "Verify response format",
+            "type": "object",
             "properties": {
                 "valid": {
                     "type": "boolean"
@@ -8,7 +8,50 @@ import (
 var (
 	ErrInvalidRequest = models.NewErrorResponse(http.StatusBadRequest, "invalid request")
 	ErrUnauthorized   = models.NewErrorResponse(http.StatusUnauthorized, "unauthorized")
+	ErrForbidden      = models.NewErrorResponse(http.StatusForbidden, "token is not allowed to perform this operation on this path")
 	ErrNotFound       = models.NewErrorResponse(http.StatusNotFound, "resource not found")
 	ErrInternalServer = models.NewErrorResponse(http.StatusInternalServerError, "internal server error")
 	ErrAPIMissingPath = models.NewErrorResponse(http.StatusBadRequest, "missing key path")
+	ErrSecretTampered = models.NewErrorResponse(http.StatusInternalServerError, "secret integrity check failed")
+	ErrSecretTooLarge = models.NewErrorResponse(http.StatusRequestEntityTooLarge, "secret exceeds the maximum size")
+
+	ErrRootTokenNotAllowed = models.NewErrorResponse(http.StatusForbidden, "root tokens can only be used for administration")
+	ErrRootTokenRequired   = models.NewErrorResponse(http.StatusForbidden, "administration requires a root token")
+
+	ErrKeyVersionActive = models.NewErrorResponse(http.StatusConflict, "the active key version cannot be retired")
+	ErrKeyVersionInUse  = models.NewErrorResponse(http.StatusConflict, "key version is still in use")
+	ErrRewrapRunning    = models.NewErrorResponse(http.StatusConflict, "a rewrap job is already running")
+
+	ErrSealed                = models.NewErrorResponse(http.StatusServiceUnavailable, "server is sealed")
+	ErrInvalidUnsealKey      = models.NewErrorResponse(http.StatusBadRequest, "invalid unseal key")
+	ErrUnsealKeyAlreadyGiven = models.NewErrorResponse(http.StatusBadRequest, "unseal key share has already been provided")
+	ErrKeyMaterialMismatch   = models.NewErrorResponse(http.StatusInternalServerError, "key material does not match the store")
+
+	ErrInvalidKeyName     = models.NewErrorResponse(http.StatusBadRequest, "key names may only contain letters, digits, underscores and dashes")
+	ErrKeyExists          = models.NewErrorResponse(http.StatusConflict, "key already exists")
+	ErrInvalidCiphertext  = models.NewErrorResponse(http.StatusBadRequest, "invalid ciphertext")
+	ErrKeyVersionDisabled = models.NewErrorResponse(http.StatusBadRequest, "ciphertext key version is below the minimum decryption version")
+	ErrUnsupportedKeyType = models.NewErrorResponse(http.StatusBadRequest, "unsupported key type")
+
+	ErrInvalidPasswordPolicy  = models.NewErrorResponse(http.StatusBadRequest, "invalid password policy")
+	ErrPasswordPolicyNotFound = models.NewErrorResponse(http.StatusBadRequest, "password policy does not exist")
+
+	ErrInvalidPolicy  = models.NewErrorResponse(http.StatusBadRequest, "invalid policy")
+	ErrPolicyNotFound = models.NewErrorResponse(http.StatusBadRequest, "policy does not exist")
+
+	ErrInvalidAppRole          = models.NewErrorResponse(http.StatusBadRequest, "invalid approle")
+	ErrInvalidAppRoleLogin     = models.NewErrorResponse(http.StatusUnauthorized, "invalid role id or secret id")
+	ErrAnonymousTokensDisabled = models.NewErrorResponse(http.StatusForbidden, "anonymous tokens are disabled, log in with an auth method instead")
+	ErrAnonymousTokenPolicies  = models.NewErrorResponse(http.StatusForbidden, "anonymous tokens cannot have policies, log in with an auth method instead")
+
+	ErrInvalidJWTConfig = models.NewErrorResponse(http.StatusBadRequest, "invalid jwt issuer or role")
+	ErrInvalidJWTLogin  = models.NewErrorResponse(http.StatusUnauthorized, "invalid or unauthorized jwt")
+
+	ErrInvalidCertRole  = models.NewErrorResponse(http.StatusBadRequest, "invalid certificate role")
+	ErrInvalidCertLogin = models.NewErrorResponse(http.StatusUnauthorized, "client certificate is missing or not allowed to log in")
+
+	ErrInvalidTokenMetadata = models.NewErrorResponse(http.StatusBadRequest, "invalid token display name or labels")
+	ErrChildTokenTTL        = models.NewErrorResponse(http.StatusBadRequest, "child token ttl and max ttl must be positive and cannot exceed the parent's")
+	ErrChildTokenPolicies   = models.NewErrorResponse(http.StatusForbidden, "child token cannot have policies its parent does not have")
+	ErrChildTokenUses       = models.NewErrorResponse(http.StatusForbidden, "child token cannot have more uses than its parent has left")
 )
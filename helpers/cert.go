@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"crypto/x509"
+	"net/http"
+)
+
+// CertificateConstraints restricts which client certificates are accepted by the subject common name, organizational
+// units and SANs they carry. Every non-empty list must match at least one value of the certificate, and its entries
+// may be globs in which `*` matches any sequence of characters, including dots.
+type CertificateConstraints struct {
+	CommonNames         []string `json:"allowed_common_names,omitempty"`
+	OrganizationalUnits []string `json:"allowed_organizational_units,omitempty"`
+	DNSNames            []string `json:"allowed_dns_sans,omitempty"`
+	EmailAddresses      []string `json:"allowed_email_sans,omitempty"`
+	URIs                []string `json:"allowed_uri_sans,omitempty"`
+}
+
+// Matches reports whether the certificate satisfies all constraints. Constraints without any entries accept every
+// certificate.
+func (c *CertificateConstraints) Matches(cert *x509.Certificate) bool {
+	uris := make([]string, len(cert.URIs))
+	for i, uri := range cert.URIs {
+		uris[i] = uri.String()
+	}
+
+	return matchAnyGlob(c.CommonNames, []string{cert.Subject.CommonName}) &&
+		matchAnyGlob(c.OrganizationalUnits, cert.Subject.OrganizationalUnit) &&
+		matchAnyGlob(c.DNSNames, cert.DNSNames) &&
+		matchAnyGlob(c.EmailAddresses, cert.EmailAddresses) &&
+		matchAnyGlob(c.URIs, uris)
+}
+
+// matchAnyGlob reports whether any value matches any of the patterns, or whether there are no patterns.
+func matchAnyGlob(patterns []string, values []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, value := range values {
+		for _, pattern := range patterns {
+			if value != "" && MatchPathGlob(pattern, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// VerifiedClientCertificate returns the client certificate of a TLS request once it has been verified against the
+// configured client CA bundle, or nil.
+func VerifiedClientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
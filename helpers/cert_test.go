@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateConstraintsMatches(t *testing.T) {
+	spiffeID, _ := url.Parse("spiffe://example.org/ns/ci/sa/runner")
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "runner.ci.internal", OrganizationalUnit: []string{"platform"}},
+		DNSNames:       []string{"runner.ci.internal", "runner"},
+		EmailAddresses: []string{"ci@example.org"},
+		URIs:           []*url.URL{spiffeID},
+	}
+
+	t.Run("empty constraints accept every certificate", func(t *testing.T) {
+		assert.True(t, (&CertificateConstraints{}).Matches(cert))
+	})
+
+	t.Run("accepts certificates matching every constraint", func(t *testing.T) {
+		constraints := CertificateConstraints{
+			CommonNames:         []string{"*.ci.internal"},
+			OrganizationalUnits: []string{"platform", "security"},
+			DNSNames:            []string{"runner"},
+			EmailAddresses:      []string{"*@example.org"},
+			URIs:                []string{"spiffe://example.org/ns/ci/*"},
+		}
+		assert.True(t, constraints.Matches(cert))
+	})
+
+	t.Run("rejects certificates failing any constraint", func(t *testing.T) {
+		assert.False(t, (&CertificateConstraints{CommonNames: []string{"*.prod.internal"}}).Matches(cert))
+		assert.False(t, (&CertificateConstraints{CommonNames: []string{"*"}, OrganizationalUnits: []string{"security"}}).Matches(cert))
+		assert.False(t, (&CertificateConstraints{URIs: []string{"spiffe://example.org/ns/prod/*"}}).Matches(cert))
+		assert.False(t, (&CertificateConstraints{EmailAddresses: []string{"*"}}).Matches(&x509.Certificate{}))
+	})
+}
+
+func TestVerifiedClientCertificate(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "runner"}}
+
+	assert.Nil(t, VerifiedClientCertificate(&http.Request{}))
+	assert.Nil(t, VerifiedClientCertificate(&http.Request{TLS: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}}))
+	assert.Equal(t, cert, VerifiedClientCertificate(&http.Request{TLS: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}))
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// ValidateCIDRs checks that every entry of a CIDR list is a valid IPv4 or IPv6 prefix, e.g. 10.0.0.0/8.
+func ValidateCIDRs(cidrs []string) error {
+	for _, cidr := range cidrs {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			return fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+	}
+	return nil
+}
+
+// CIDRsContain reports whether the IP address is in any of the CIDRs. An empty list does not restrict the address
+// and contains every IP, while an address that cannot be parsed is in none.
+func CIDRsContain(cidrs []string, ip string) bool {
+	if len(cidrs) == 0 {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
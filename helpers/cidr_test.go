@@ -0,0 +1,40 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCIDRs(t *testing.T) {
+	t.Run("accepts valid CIDRs", func(t *testing.T) {
+		assert.NoError(t, ValidateCIDRs(nil))
+		assert.NoError(t, ValidateCIDRs([]string{"10.0.0.0/8", "192.168.1.10/32", "fd00::/8"}))
+	})
+
+	t.Run("returns error for invalid CIDRs", func(t *testing.T) {
+		assert.Error(t, ValidateCIDRs([]string{"10.0.0.0"}))
+		assert.Error(t, ValidateCIDRs([]string{"10.0.0.0/8", "not-a-cidr"}))
+		assert.Error(t, ValidateCIDRs([]string{"10.0.0.0/33"}))
+	})
+}
+
+func TestCIDRsContain(t *testing.T) {
+	cidrs := []string{"10.0.0.0/8", "fd00::/8"}
+
+	t.Run("an empty list contains every address", func(t *testing.T) {
+		assert.True(t, CIDRsContain(nil, "203.0.113.7"))
+	})
+
+	t.Run("matches addresses in the CIDRs", func(t *testing.T) {
+		assert.True(t, CIDRsContain(cidrs, "10.1.2.3"))
+		assert.True(t, CIDRsContain(cidrs, "::ffff:10.1.2.3"))
+		assert.True(t, CIDRsContain(cidrs, "fd00::1"))
+	})
+
+	t.Run("rejects addresses outside the CIDRs", func(t *testing.T) {
+		assert.False(t, CIDRsContain(cidrs, "11.0.0.1"))
+		assert.False(t, CIDRsContain(cidrs, "fe80::1"))
+		assert.False(t, CIDRsContain(cidrs, "not-an-ip"))
+	})
+}
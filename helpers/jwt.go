@@ -0,0 +1,353 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidJWT      = errors.New("invalid jwt")
+	ErrJWTSignature    = errors.New("jwt signature verification failed")
+	ErrJWKNotFound     = errors.New("no key matches the jwt")
+	ErrJWTExpired      = errors.New("jwt has expired")
+	ErrJWTNotYetValid  = errors.New("jwt is not valid yet")
+	ErrUnsupportedJWK  = errors.New("unsupported jwk")
+	ErrUnsupportedAlg  = errors.New("unsupported jwt algorithm")
+	ErrJWTMissingClaim = errors.New("jwt is missing a required claim")
+)
+
+// JWTHeader is the protected header of a JWT.
+type JWTHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// JWT is a parsed, not yet verified, JSON Web Token signed with JWS compact serialization.
+type JWT struct {
+	Header    JWTHeader
+	Claims    map[string]any
+	Signature []byte
+
+	signingInput string
+}
+
+// JWK is a public JSON Web Key of type RSA, EC (P-256, P-384 or P-521) or OKP (Ed25519).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set as served by an issuer's jwks_uri.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// jwtAlgorithm describes how a JWS algorithm verifies signatures.
+type jwtAlgorithm struct {
+	kty  string
+	hash crypto.Hash
+	crv  string // Curve of EC keys
+	pss  bool   // RSASSA-PSS instead of PKCS #1 v1.5
+}
+
+var jwtAlgorithms = map[string]jwtAlgorithm{
+	"RS256": {kty: "RSA", hash: crypto.SHA256},
+	"RS384": {kty: "RSA", hash: crypto.SHA384},
+	"RS512": {kty: "RSA", hash: crypto.SHA512},
+	"PS256": {kty: "RSA", hash: crypto.SHA256, pss: true},
+	"PS384": {kty: "RSA", hash: crypto.SHA384, pss: true},
+	"PS512": {kty: "RSA", hash: crypto.SHA512, pss: true},
+	"ES256": {kty: "EC", hash: crypto.SHA256, crv: "P-256"},
+	"ES384": {kty: "EC", hash: crypto.SHA384, crv: "P-384"},
+	"ES512": {kty: "EC", hash: crypto.SHA512, crv: "P-521"},
+	"EdDSA": {kty: "OKP", crv: "Ed25519"},
+}
+
+// minRSAKeyBits is the smallest RSA modulus accepted for verifying JWTs.
+const minRSAKeyBits = 2048
+
+// ParseJWT decodes the header and claims of a JWT without verifying it. Unsigned tokens and tokens signed with a
+// shared secret are rejected, only the asymmetric algorithms a JWKS can verify are accepted.
+func ParseJWT(token string) (*JWT, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected 3 parts, got %d", ErrInvalidJWT, len(parts))
+	}
+
+	var jwt JWT
+	if err := decodeJWTPart(parts[0], &jwt.Header); err != nil {
+		return nil, fmt.Errorf("%w: invalid header: %v", ErrInvalidJWT, err)
+	}
+	if _, ok := jwtAlgorithms[jwt.Header.Alg]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlg, jwt.Header.Alg)
+	}
+	if err := decodeJWTPart(parts[1], &jwt.Claims); err != nil || jwt.Claims == nil {
+		return nil, fmt.Errorf("%w: invalid claims: %v", ErrInvalidJWT, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid signature encoding: %v", ErrInvalidJWT, err)
+	}
+	jwt.Signature = signature
+	jwt.signingInput = parts[0] + "." + parts[1]
+	return &jwt, nil
+}
+
+// decodeJWTPart decodes a base64url encoded JSON part of a JWT.
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Verify checks the signature of the JWT against the keys of a key set. When the JWT names a key ID only that key is
+// tried, otherwise every key of the type its algorithm needs.
+func (j *JWT) Verify(keys []JWK) error {
+	algorithm := jwtAlgorithms[j.Header.Alg]
+
+	tried := false
+	for _, key := range keys {
+		if (j.Header.Kid != "" && key.Kid != j.Header.Kid) || key.Kty != algorithm.kty {
+			continue
+		}
+		if (key.Alg != "" && key.Alg != j.Header.Alg) || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+
+		publicKey, err := key.PublicKey()
+		if err != nil {
+			continue
+		}
+		tried = true
+		if verifyJWTSignature(algorithm, publicKey, j.signingInput, j.Signature) {
+			return nil
+		}
+	}
+	if !tried {
+		return ErrJWKNotFound
+	}
+	return ErrJWTSignature
+}
+
+// verifyJWTSignature verifies a JWS signature of the signing input with a public key.
+func verifyJWTSignature(algorithm jwtAlgorithm, publicKey crypto.PublicKey, signingInput string, signature []byte) bool {
+	if key, ok := publicKey.(ed25519.PublicKey); ok {
+		return algorithm.kty == "OKP" && ed25519.Verify(key, []byte(signingInput), signature)
+	}
+
+	hasher := algorithm.hash.New()
+	hasher.Write([]byte(signingInput))
+	digest := hasher.Sum(nil)
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if algorithm.pss {
+			return rsa.VerifyPSS(key, algorithm.hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+		return rsa.VerifyPKCS1v15(key, algorithm.hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		// ECDSA signatures are the fixed size big-endian r and s, not ASN.1
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if key.Curve.Params().Name != algorithm.crv || len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+	return false
+}
+
+// PublicKey decodes the public key the JWK describes.
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(n) == 0 {
+			return nil, fmt.Errorf("%w: invalid RSA modulus", ErrUnsupportedJWK)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("%w: invalid RSA exponent", ErrUnsupportedJWK)
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if key.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("%w: RSA keys must have at least %d bits", ErrUnsupportedJWK, minRSAKeyBits)
+		}
+		return key, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: unsupported curve %q", ErrUnsupportedJWK, k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("%w: invalid EC coordinates", ErrUnsupportedJWK)
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("%w: EC point is not on the curve", ErrUnsupportedJWK)
+		}
+		return key, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if k.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: only Ed25519 OKP keys are supported", ErrUnsupportedJWK)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported key type %q", ErrUnsupportedJWK, k.Kty)
+}
+
+// ParseJWKS decodes a JSON Web Key Set and checks that it holds at least one usable key.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var jwks JWKS
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+	for _, key := range jwks.Keys {
+		if _, err := key.PublicKey(); err == nil {
+			return &jwks, nil
+		}
+	}
+	return nil, errors.New("jwks contains no supported keys")
+}
+
+// ValidateTime checks the exp, nbf and iat claims of the JWT, allowing for the given clock skew. The exp claim is
+// required.
+func (j *JWT) ValidateTime(now time.Time, leeway time.Duration) error {
+	exp, ok := j.numericDate("exp")
+	if !ok {
+		return fmt.Errorf("%w: exp", ErrJWTMissingClaim)
+	}
+	if now.After(exp.Add(leeway)) {
+		return ErrJWTExpired
+	}
+	if nbf, ok := j.numericDate("nbf"); ok && now.Add(leeway).Before(nbf) {
+		return ErrJWTNotYetValid
+	}
+	if iat, ok := j.numericDate("iat"); ok && now.Add(leeway).Before(iat) {
+		return ErrJWTNotYetValid
+	}
+	return nil
+}
+
+// numericDate returns a claim holding seconds since the epoch as a time.
+func (j *JWT) numericDate(name string) (time.Time, bool) {
+	value, ok := j.Claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(value), 0), true
+}
+
+// Audiences returns the aud claim, which may be a single string or a list of strings.
+func (j *JWT) Audiences() []string {
+	return claimStrings(j.Claims["aud"])
+}
+
+// Claim returns the value of a claim. Names starting with a slash are JSON pointers into nested claims, e.g.
+// /kubernetes.io/namespace.
+func (j *JWT) Claim(name string) (any, bool) {
+	if !strings.HasPrefix(name, "/") {
+		value, ok := j.Claims[name]
+		return value, ok
+	}
+
+	var value any = j.Claims
+	for _, segment := range strings.Split(name[1:], "/") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		segment = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+		if value, ok = object[segment]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// ClaimString returns a claim that holds a string, number or boolean as a string.
+func (j *JWT) ClaimString(name string) (string, bool) {
+	value, ok := j.Claim(name)
+	if !ok {
+		return "", false
+	}
+	values := claimStrings(value)
+	if _, isList := value.([]any); isList || len(values) != 1 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// MatchClaim reports whether a claim matches any of the allowed values, which may be globs in which `*` matches any
+// sequence of characters. A claim holding a list matches when any of its entries does.
+func (j *JWT) MatchClaim(name string, allowed []string) bool {
+	value, ok := j.Claim(name)
+	if !ok {
+		return false
+	}
+	for _, claim := range claimStrings(value) {
+		for _, pattern := range allowed {
+			if MatchPathGlob(pattern, claim) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// claimStrings converts a claim holding a string, number, boolean or a list of them to strings. Objects are
+// skipped.
+func claimStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case []any:
+		var values []string
+		for _, entry := range v {
+			if _, isList := entry.([]any); !isList {
+				values = append(values, claimStrings(entry)...)
+			}
+		}
+		return values
+	}
+	return nil
+}
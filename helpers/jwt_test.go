@@ -0,0 +1,206 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signTestJWT builds a JWT with the given header and claims signed with the private key.
+func signTestJWT(t *testing.T, header JWTHeader, claims map[string]any, key crypto.Signer) string {
+	t.Helper()
+
+	headerJSON, err := json.Marshal(header)
+	require.NoError(t, err)
+	claimsJSON, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	var signature []byte
+	algorithm := jwtAlgorithms[header.Alg]
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signingInput))
+	case *rsa.PrivateKey:
+		hasher := algorithm.hash.New()
+		hasher.Write([]byte(signingInput))
+		if algorithm.pss {
+			signature, err = rsa.SignPSS(rand.Reader, k, algorithm.hash, hasher.Sum(nil), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, k, algorithm.hash, hasher.Sum(nil))
+		}
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		hasher := algorithm.hash.New()
+		hasher.Write([]byte(signingInput))
+		r, s, err := ecdsa.Sign(rand.Reader, k, hasher.Sum(nil))
+		require.NoError(t, err)
+		size := (k.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys := []JWK{
+		{Kty: "RSA", Kid: "rsa", N: base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{Kty: "EC", Kid: "ec", Crv: "P-256", X: base64.RawURLEncoding.EncodeToString(ecKey.X.Bytes()), Y: base64.RawURLEncoding.EncodeToString(ecKey.Y.Bytes())},
+		{Kty: "OKP", Kid: "ed", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(edPublic)},
+	}
+	claims := map[string]any{"sub": "runner", "exp": time.Now().Add(time.Hour).Unix()}
+
+	t.Run("verifies supported algorithms", func(t *testing.T) {
+		for _, tc := range []struct {
+			header JWTHeader
+			key    crypto.Signer
+		}{
+			{JWTHeader{Alg: "RS256", Kid: "rsa"}, rsaKey},
+			{JWTHeader{Alg: "PS384", Kid: "rsa"}, rsaKey},
+			{JWTHeader{Alg: "ES256", Kid: "ec"}, ecKey},
+			{JWTHeader{Alg: "EdDSA", Kid: "ed"}, edKey},
+			{JWTHeader{Alg: "RS512"}, rsaKey},
+		} {
+			jwt, err := ParseJWT(signTestJWT(t, tc.header, claims, tc.key))
+			require.NoError(t, err, tc.header.Alg)
+			assert.NoError(t, jwt.Verify(keys), tc.header.Alg)
+		}
+	})
+
+	t.Run("rejects tampered claims", func(t *testing.T) {
+		parts := strings.Split(signTestJWT(t, JWTHeader{Alg: "RS256", Kid: "rsa"}, claims, rsaKey), ".")
+		tampered, err := json.Marshal(map[string]any{"sub": "admin", "exp": claims["exp"]})
+		require.NoError(t, err)
+		parts[1] = base64.RawURLEncoding.EncodeToString(tampered)
+
+		jwt, err := ParseJWT(strings.Join(parts, "."))
+		require.NoError(t, err)
+		assert.ErrorIs(t, jwt.Verify(keys), ErrJWTSignature)
+	})
+
+	t.Run("rejects tokens signed by another key", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		jwt, err := ParseJWT(signTestJWT(t, JWTHeader{Alg: "ES256", Kid: "ec"}, claims, otherKey))
+		require.NoError(t, err)
+		assert.ErrorIs(t, jwt.Verify(keys), ErrJWTSignature)
+	})
+
+	t.Run("returns error for unknown key IDs", func(t *testing.T) {
+		jwt, err := ParseJWT(signTestJWT(t, JWTHeader{Alg: "ES256", Kid: "unknown"}, claims, ecKey))
+		require.NoError(t, err)
+		assert.ErrorIs(t, jwt.Verify(keys), ErrJWKNotFound)
+	})
+
+	t.Run("rejects unsigned and symmetric tokens", func(t *testing.T) {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"runner"}`))
+		for _, alg := range []string{"none", "HS256"} {
+			header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"` + alg + `"}`))
+			_, err := ParseJWT(header + "." + payload + ".")
+			assert.ErrorIs(t, err, ErrUnsupportedAlg, alg)
+		}
+	})
+
+	t.Run("returns error for malformed tokens", func(t *testing.T) {
+		_, err := ParseJWT("not-a-jwt")
+		assert.ErrorIs(t, err, ErrInvalidJWT)
+		_, err = ParseJWT("a.b.c")
+		assert.ErrorIs(t, err, ErrInvalidJWT)
+	})
+}
+
+func TestParseJWKS(t *testing.T) {
+	t.Run("accepts a key set with a supported key", func(t *testing.T) {
+		jwks, err := ParseJWKS([]byte(`{"keys":[{"kty":"oct","k":"c2VjcmV0"},{"kty":"OKP","crv":"Ed25519","x":"` + base64.RawURLEncoding.EncodeToString(make([]byte, 32)) + `"}]}`))
+		require.NoError(t, err)
+		assert.Len(t, jwks.Keys, 2)
+	})
+
+	t.Run("returns error without supported keys", func(t *testing.T) {
+		_, err := ParseJWKS([]byte(`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`))
+		assert.Error(t, err)
+		_, err = ParseJWKS([]byte(`not json`))
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects short RSA keys", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+
+		key := JWK{Kty: "RSA", N: base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), E: "AQAB"}
+		_, err = key.PublicKey()
+		assert.ErrorIs(t, err, ErrUnsupportedJWK)
+	})
+}
+
+func TestJWTValidateTime(t *testing.T) {
+	now := time.Now()
+	jwt := func(claims map[string]any) *JWT { return &JWT{Claims: claims} }
+	unix := func(d time.Duration) float64 { return float64(now.Add(d).Unix()) }
+
+	assert.NoError(t, jwt(map[string]any{"exp": unix(time.Minute), "nbf": unix(-time.Minute), "iat": unix(-time.Minute)}).ValidateTime(now, 0))
+	assert.NoError(t, jwt(map[string]any{"exp": unix(-30 * time.Second)}).ValidateTime(now, time.Minute))
+	assert.ErrorIs(t, jwt(map[string]any{}).ValidateTime(now, 0), ErrJWTMissingClaim)
+	assert.ErrorIs(t, jwt(map[string]any{"exp": unix(-time.Minute)}).ValidateTime(now, 0), ErrJWTExpired)
+	assert.ErrorIs(t, jwt(map[string]any{"exp": unix(time.Hour), "nbf": unix(time.Minute)}).ValidateTime(now, 0), ErrJWTNotYetValid)
+}
+
+func TestJWTClaims(t *testing.T) {
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"sub": "system:serviceaccount:ci:runner",
+		"aud": ["vault", "go-secrets"],
+		"groups": ["dev", "ops"],
+		"run_id": 1234567890,
+		"kubernetes.io": { "namespace": "ci" }
+	}`), &claims))
+	jwt := &JWT{Claims: claims}
+
+	t.Run("reads audiences", func(t *testing.T) {
+		assert.Equal(t, []string{"vault", "go-secrets"}, jwt.Audiences())
+		assert.Equal(t, []string{"go-secrets"}, (&JWT{Claims: map[string]any{"aud": "go-secrets"}}).Audiences())
+	})
+
+	t.Run("reads nested claims", func(t *testing.T) {
+		value, ok := jwt.ClaimString("/kubernetes.io/namespace")
+		assert.True(t, ok)
+		assert.Equal(t, "ci", value)
+
+		value, ok = jwt.ClaimString("run_id")
+		assert.True(t, ok)
+		assert.Equal(t, "1234567890", value)
+
+		_, ok = jwt.ClaimString("groups")
+		assert.False(t, ok)
+		_, ok = jwt.ClaimString("/kubernetes.io/missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("matches claims against allowed values", func(t *testing.T) {
+		assert.True(t, jwt.MatchClaim("sub", []string{"system:serviceaccount:ci:*"}))
+		assert.True(t, jwt.MatchClaim("groups", []string{"ops"}))
+		assert.True(t, jwt.MatchClaim("/kubernetes.io/namespace", []string{"prod", "ci"}))
+		assert.False(t, jwt.MatchClaim("sub", []string{"system:serviceaccount:prod:*"}))
+		assert.False(t, jwt.MatchClaim("groups", []string{"admin"}))
+		assert.False(t, jwt.MatchClaim("missing", []string{"*"}))
+	})
+}
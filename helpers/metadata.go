@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	MaxDisplayNameLength = 128
+	MaxLabels            = 32
+	MaxLabelValueLength  = 256
+)
+
+// ValidateTokenMetadata checks the display name and labels a token is issued with. Label keys follow the same rules
+// as key names, values and the display name are limited in length.
+func ValidateTokenMetadata(displayName string, labels map[string]string) error {
+	if utf8.RuneCountInString(displayName) > MaxDisplayNameLength {
+		return fmt.Errorf("display name cannot be longer than %d characters", MaxDisplayNameLength)
+	}
+	if len(labels) > MaxLabels {
+		return fmt.Errorf("token cannot have more than %d labels", MaxLabels)
+	}
+	for key, value := range labels {
+		if err := ValidateKeyName(key); err != nil {
+			return fmt.Errorf("invalid label: %w", err)
+		}
+		if utf8.RuneCountInString(value) > MaxLabelValueLength {
+			return fmt.Errorf("label %q cannot be longer than %d characters", key, MaxLabelValueLength)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTokenMetadata(t *testing.T) {
+	t.Run("accepts valid metadata", func(t *testing.T) {
+		assert.NoError(t, ValidateTokenMetadata("", nil))
+		assert.NoError(t, ValidateTokenMetadata("deploy job", map[string]string{"team": "ci", "run_id": "1234"}))
+	})
+
+	t.Run("returns error for invalid metadata", func(t *testing.T) {
+		labels := map[string]string{}
+		for i := 0; i <= MaxLabels; i++ {
+			labels[strings.Repeat("a", i+1)] = "value"
+		}
+
+		assert.Error(t, ValidateTokenMetadata(strings.Repeat("a", MaxDisplayNameLength+1), nil))
+		assert.Error(t, ValidateTokenMetadata("", labels))
+		assert.Error(t, ValidateTokenMetadata("", map[string]string{"bad key": "value"}))
+		assert.Error(t, ValidateTokenMetadata("", map[string]string{"key": strings.Repeat("a", MaxLabelValueLength+1)}))
+	})
+}
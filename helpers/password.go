@@ -0,0 +1,193 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	DefaultPasswordLength = 24
+	MaxGeneratedLength    = 1024
+)
+
+// PasswordClasses maps the character classes a generated password can draw from to their characters.
+var PasswordClasses = map[string]string{
+	"lowercase": "abcdefghijklmnopqrstuvwxyz",
+	"uppercase": "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"digits":    "0123456789",
+	"symbols":   "!#$%&()*+,-./:;<=>?@[]^_{|}~",
+}
+
+// passwordClassOrder lists the character classes in a fixed order, so generation does not depend on map iteration.
+var passwordClassOrder = []string{"lowercase", "uppercase", "digits", "symbols"}
+
+// PasswordPolicy describes how to generate a secret value: either a password drawn from character classes,
+// or raw random bytes encoded as hex or base64.
+// @Description Password policy format
+// @Example { "length": 32, "classes": ["lowercase", "uppercase", "digits"], "exclude": "0O1l", "min_counts": { "digits": 2 } }
+type PasswordPolicy struct {
+	Length    int            `json:"length,omitempty"`     // Password length, defaults to 24
+	Classes   []string       `json:"classes,omitempty"`    // lowercase, uppercase, digits and/or symbols, defaults to all
+	Exclude   string         `json:"exclude,omitempty"`    // Characters never to use
+	MinCounts map[string]int `json:"min_counts,omitempty"` // Minimum number of characters per class
+	Bytes     int            `json:"bytes,omitempty"`      // Generate this many random bytes instead of a password
+	Encoding  string         `json:"encoding,omitempty"`   // hex or base64 encoding of random bytes, defaults to hex
+}
+
+// Validate checks that the policy is consistent and can be satisfied.
+func (p *PasswordPolicy) Validate() error {
+	if p.Bytes != 0 {
+		if p.Length != 0 || len(p.Classes) != 0 || p.Exclude != "" || len(p.MinCounts) != 0 {
+			return errors.New("random bytes cannot be combined with password options")
+		}
+		if p.Bytes < 0 || p.Bytes > MaxGeneratedLength {
+			return fmt.Errorf("bytes must be between 1 and %d", MaxGeneratedLength)
+		}
+		if p.Encoding != "" && p.Encoding != "hex" && p.Encoding != "base64" {
+			return fmt.Errorf("unsupported encoding %q, expected hex or base64", p.Encoding)
+		}
+		return nil
+	}
+
+	if p.Encoding != "" {
+		return errors.New("encoding only applies to random bytes")
+	}
+
+	length := p.length()
+	if length < 1 || length > MaxGeneratedLength {
+		return fmt.Errorf("length must be between 1 and %d", MaxGeneratedLength)
+	}
+
+	charsets, err := p.charsets()
+	if err != nil {
+		return err
+	}
+
+	required := 0
+	for class, count := range p.MinCounts {
+		if _, ok := charsets[class]; !ok {
+			return fmt.Errorf("minimum count given for unused character class %q", class)
+		}
+		if count < 0 {
+			return fmt.Errorf("minimum count for %q cannot be negative", class)
+		}
+		required += count
+	}
+	if required > length {
+		return fmt.Errorf("minimum counts add up to %d, more than the length of %d", required, length)
+	}
+	return nil
+}
+
+// IsEmpty reports whether none of the policy's options are set.
+func (p *PasswordPolicy) IsEmpty() bool {
+	return p.Length == 0 && len(p.Classes) == 0 && p.Exclude == "" && len(p.MinCounts) == 0 && p.Bytes == 0 && p.Encoding == ""
+}
+
+// GenerateValue generates a secret value from the policy using crypto/rand.
+func GenerateValue(p PasswordPolicy) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	if p.Bytes != 0 {
+		data := make([]byte, p.Bytes)
+		if _, err := rand.Read(data); err != nil {
+			return "", err
+		}
+		if p.Encoding == "base64" {
+			return base64.StdEncoding.EncodeToString(data), nil
+		}
+		return hex.EncodeToString(data), nil
+	}
+
+	charsets, err := p.charsets()
+	if err != nil {
+		return "", err
+	}
+
+	password := make([]byte, 0, p.length())
+	var all strings.Builder
+	for _, class := range passwordClassOrder {
+		charset, ok := charsets[class]
+		if !ok {
+			continue
+		}
+		all.WriteString(charset)
+		for i := 0; i < p.MinCounts[class]; i++ {
+			c, err := randomChar(charset)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, c)
+		}
+	}
+
+	for len(password) < p.length() {
+		c, err := randomChar(all.String())
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Shuffle so the characters satisfying minimum counts are not at predictable positions
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func (p *PasswordPolicy) length() int {
+	if p.Length == 0 {
+		return DefaultPasswordLength
+	}
+	return p.Length
+}
+
+// charsets returns the characters of each selected class with excluded characters removed.
+func (p *PasswordPolicy) charsets() (map[string]string, error) {
+	classes := p.Classes
+	if len(classes) == 0 {
+		classes = passwordClassOrder
+	}
+
+	charsets := make(map[string]string, len(classes))
+	for _, class := range classes {
+		chars, ok := PasswordClasses[class]
+		if !ok {
+			return nil, fmt.Errorf("unknown character class %q", class)
+		}
+
+		charset := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(p.Exclude, r) {
+				return -1
+			}
+			return r
+		}, chars)
+		if charset == "" {
+			return nil, fmt.Errorf("all characters of class %q are excluded", class)
+		}
+		charsets[class] = charset
+	}
+	return charsets, nil
+}
+
+// randomChar picks a uniformly random character from the charset.
+func randomChar(charset string) (byte, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[i.Int64()], nil
+}
@@ -0,0 +1,82 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateValue(t *testing.T) {
+	t.Run("generates password with default length from all classes", func(t *testing.T) {
+		value, err := GenerateValue(PasswordPolicy{})
+
+		assert.NoError(t, err)
+		assert.Len(t, value, DefaultPasswordLength)
+	})
+
+	t.Run("uses only selected classes and skips excluded characters", func(t *testing.T) {
+		value, err := GenerateValue(PasswordPolicy{Length: 200, Classes: []string{"digits"}, Exclude: "01"})
+
+		assert.NoError(t, err)
+		assert.Len(t, value, 200)
+		for _, c := range value {
+			assert.Contains(t, "23456789", string(c))
+		}
+	})
+
+	t.Run("satisfies minimum counts per class", func(t *testing.T) {
+		policy := PasswordPolicy{Length: 8, Classes: []string{"lowercase", "symbols"}, MinCounts: map[string]int{"symbols": 8}}
+
+		value, err := GenerateValue(policy)
+
+		assert.NoError(t, err)
+		for _, c := range value {
+			assert.Contains(t, PasswordClasses["symbols"], string(c))
+		}
+	})
+
+	t.Run("generates hex and base64 encoded random bytes", func(t *testing.T) {
+		value, err := GenerateValue(PasswordPolicy{Bytes: 16})
+		assert.NoError(t, err)
+		decoded, err := hex.DecodeString(value)
+		assert.NoError(t, err)
+		assert.Len(t, decoded, 16)
+
+		value, err = GenerateValue(PasswordPolicy{Bytes: 16, Encoding: "base64"})
+		assert.NoError(t, err)
+		decoded, err = base64.StdEncoding.DecodeString(value)
+		assert.NoError(t, err)
+		assert.Len(t, decoded, 16)
+	})
+
+	t.Run("returns error for invalid policies", func(t *testing.T) {
+		policies := []PasswordPolicy{
+			{Length: MaxGeneratedLength + 1},
+			{Classes: []string{"emoji"}},
+			{Classes: []string{"digits"}, Exclude: "0123456789"},
+			{Length: 4, MinCounts: map[string]int{"digits": 5}},
+			{Classes: []string{"digits"}, MinCounts: map[string]int{"symbols": 1}},
+			{Bytes: 16, Length: 16},
+			{Bytes: 16, Encoding: "base32"},
+			{Encoding: "hex"},
+		}
+
+		for _, policy := range policies {
+			value, err := GenerateValue(policy)
+
+			assert.Error(t, err)
+			assert.Empty(t, value)
+		}
+	})
+
+	t.Run("generates different values", func(t *testing.T) {
+		first, err := GenerateValue(PasswordPolicy{})
+		assert.NoError(t, err)
+		second, err := GenerateValue(PasswordPolicy{})
+		assert.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+	})
+}
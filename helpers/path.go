@@ -2,8 +2,12 @@ package helpers
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 )
 
+var keyNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
 // FormatSecretPath formats the secret path by combining HMAC and key into a single string.
 func FormatSecretPath(namespace string, key string) (string, error) {
 	if namespace == "" || key == "" {
@@ -11,3 +15,20 @@ func FormatSecretPath(namespace string, key string) (string, error) {
 	}
 	return fmt.Sprintf("%s:secret:%s", namespace, key), nil
 }
+
+// ParseSecretPath splits a secret path produced by FormatSecretPath into its namespace and key.
+func ParseSecretPath(path string) (string, string, error) {
+	namespace, key, found := strings.Cut(path, ":secret:")
+	if !found || namespace == "" || key == "" {
+		return "", "", fmt.Errorf("invalid secret path: %s", path)
+	}
+	return namespace, key, nil
+}
+
+// ValidateKeyName checks that a name for a server managed key only contains letters, digits, underscores and dashes.
+func ValidateKeyName(name string) error {
+	if !keyNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid key name: %q", name)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,3 +47,42 @@ func TestFormatSecretPath(t *testing.T) {
 		assert.EqualError(t, err, "namespace and key cannot be empty")
 	})
 }
+
+func TestParseSecretPath(t *testing.T) {
+	t.Run("parses secret path correctly", func(t *testing.T) {
+		namespace, key, err := ParseSecretPath("my_namespace:secret:db/password")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "my_namespace", namespace)
+		assert.Equal(t, "db/password", key)
+	})
+
+	t.Run("keeps separators inside the key", func(t *testing.T) {
+		namespace, key, err := ParseSecretPath("my_namespace:secret:app:secret:token")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "my_namespace", namespace)
+		assert.Equal(t, "app:secret:token", key)
+	})
+
+	t.Run("returns error when path is not a secret path", func(t *testing.T) {
+		namespace, key, err := ParseSecretPath("my_namespace")
+
+		assert.Error(t, err)
+		assert.Empty(t, namespace)
+		assert.Empty(t, key)
+		assert.EqualError(t, err, "invalid secret path: my_namespace")
+	})
+}
+
+func TestValidateKeyName(t *testing.T) {
+	t.Run("accepts letters, digits, underscores and dashes", func(t *testing.T) {
+		assert.NoError(t, ValidateKeyName("payments_key-2"))
+	})
+
+	t.Run("returns error for invalid names", func(t *testing.T) {
+		for _, name := range []string{"", "with:colon", "with/slash", "with space", strings.Repeat("a", 129)} {
+			assert.Error(t, ValidateKeyName(name), name)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	CapabilityRead   = "read"
+	CapabilityWrite  = "write"
+	CapabilityDelete = "delete"
+	CapabilityList   = "list"
+)
+
+// Capabilities lists every capability a policy rule can grant.
+var Capabilities = []string{CapabilityRead, CapabilityWrite, CapabilityDelete, CapabilityList}
+
+// PolicyRule grants capabilities on the request paths matching a glob, in which `*` matches any sequence of
+// characters including slashes.
+type PolicyRule struct {
+	Path         string   `json:"path"`         // Path glob, e.g. secret/ci/*
+	Capabilities []string `json:"capabilities"` // read, write, delete and/or list
+}
+
+// Policy is a named set of rules restricting what a token may do. A request is allowed when any rule matching
+// its path grants the capability it needs.
+// @Description Token policy format
+// @Example { "rules": [ { "path": "secret/ci/*", "capabilities": ["read", "list"] } ] }
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// Validate checks that every rule has a path and only known capabilities.
+func (p *Policy) Validate() error {
+	if len(p.Rules) == 0 {
+		return errors.New("policy must have at least one rule")
+	}
+	for _, rule := range p.Rules {
+		if rule.Path == "" {
+			return errors.New("rule path cannot be empty")
+		}
+		if len(rule.Capabilities) == 0 {
+			return fmt.Errorf("rule for %q grants no capabilities", rule.Path)
+		}
+		for _, capability := range rule.Capabilities {
+			if !slices.Contains(Capabilities, capability) {
+				return fmt.Errorf("unknown capability %q, expected one of %v", capability, Capabilities)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the policy grants the capability on the path.
+func (p *Policy) Allows(path string, capability string) bool {
+	for _, rule := range p.Rules {
+		if slices.Contains(rule.Capabilities, capability) && MatchPathGlob(rule.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPathGlob reports whether the path matches the glob, in which `*` matches any sequence of characters
+// including slashes and every other character matches itself.
+func MatchPathGlob(pattern string, path string) bool {
+	// Iterative matching with backtracking to the last star, linear in practice
+	p, s := 0, 0
+	star, match := -1, 0
+	for s < len(path) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, match = p, s
+			p++
+		case p < len(pattern) && pattern[p] == path[s]:
+			p++
+			s++
+		case star >= 0:
+			match++
+			p, s = star+1, match
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
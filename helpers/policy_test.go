@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"secret/ci/*", "secret/ci/db", true},
+		{"secret/ci/*", "secret/ci/nested/db", true},
+		{"secret/ci/*", "secret/ci", false},
+		{"secret/ci/*", "secret/cd/db", false},
+		{"secret/*/password", "secret/app/password", true},
+		{"secret/*/password", "secret/app/username", false},
+		{"secret/db", "secret/db", true},
+		{"secret/db", "secret/db2", false},
+		{"*", "anything/at/all", true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.match, MatchPathGlob(test.pattern, test.path), "%s against %s", test.pattern, test.path)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	policy := Policy{Rules: []PolicyRule{
+		{Path: "secret/ci/*", Capabilities: []string{CapabilityRead, CapabilityList}},
+		{Path: "secret/ci/cache", Capabilities: []string{CapabilityWrite}},
+	}}
+
+	t.Run("allows capabilities granted by any matching rule", func(t *testing.T) {
+		assert.NoError(t, policy.Validate())
+		assert.True(t, policy.Allows("secret/ci/db", CapabilityRead))
+		assert.True(t, policy.Allows("secret/ci/cache", CapabilityWrite))
+		assert.False(t, policy.Allows("secret/ci/db", CapabilityWrite))
+		assert.False(t, policy.Allows("secret/ci/db", CapabilityDelete))
+		assert.False(t, policy.Allows("secret/prod/db", CapabilityRead))
+	})
+
+	t.Run("returns error for invalid policies", func(t *testing.T) {
+		policies := []Policy{
+			{},
+			{Rules: []PolicyRule{{Path: "", Capabilities: []string{CapabilityRead}}}},
+			{Rules: []PolicyRule{{Path: "secret/*"}}},
+			{Rules: []PolicyRule{{Path: "secret/*", Capabilities: []string{"sudo"}}}},
+		}
+
+		for _, policy := range policies {
+			assert.Error(t, policy.Validate())
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// gf256Exp and gf256Log are the exponent and logarithm tables of GF(2^8) with the AES polynomial and generator 3.
+var gf256Exp [510]byte
+var gf256Log [256]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gf256Exp[i] = x
+		gf256Exp[i+255] = x
+		gf256Log[x] = byte(i)
+		x ^= gf256Double(x)
+	}
+}
+
+// gf256Double multiplies a field element by 2, reducing modulo the AES polynomial x^8 + x^4 + x^3 + x + 1.
+func gf256Double(a byte) byte {
+	if a&0x80 != 0 {
+		return a<<1 ^ 0x1b
+	}
+	return a << 1
+}
+
+func gf256Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+int(gf256Log[b])]
+}
+
+func gf256Div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+255-int(gf256Log[b])]
+}
+
+// SplitSecret splits the secret into n shares using Shamir's secret sharing over GF(256), so that any threshold
+// of them reconstruct it and fewer reveal nothing. Each share holds one byte per secret byte followed by its
+// x coordinate.
+func SplitSecret(secret []byte, n int, threshold int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if threshold < 2 || threshold > n || n > 255 {
+		return nil, fmt.Errorf("invalid share configuration: %d shares with threshold %d", n, threshold)
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1)
+	}
+
+	coefficients := make([]byte, threshold)
+	for pos, secretByte := range secret {
+		coefficients[0] = secretByte
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+
+		for _, share := range shares {
+			x := share[len(secret)]
+			// Evaluate the polynomial at x using Horner's method
+			y := byte(0)
+			for i := threshold - 1; i >= 0; i-- {
+				y = gf256Mul(y, x) ^ coefficients[i]
+			}
+			share[pos] = y
+		}
+	}
+
+	return shares, nil
+}
+
+// CombineShares reconstructs a secret from shares produced by SplitSecret using Lagrange interpolation.
+// Combining fewer shares than the threshold yields an unrelated value rather than an error.
+func CombineShares(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("at least two shares are required")
+	}
+
+	size := len(shares[0])
+	if size < 2 {
+		return nil, errors.New("invalid share length")
+	}
+
+	xs := make([]byte, len(shares))
+	seen := make(map[byte]bool, len(shares))
+	for i, share := range shares {
+		if len(share) != size {
+			return nil, errors.New("shares must have the same length")
+		}
+		x := share[size-1]
+		if x == 0 || seen[x] {
+			return nil, errors.New("shares must have distinct, non-zero x coordinates")
+		}
+		seen[x] = true
+		xs[i] = x
+	}
+
+	secret := make([]byte, size-1)
+	for pos := range secret {
+		value := byte(0)
+		for i, share := range shares {
+			// Lagrange basis polynomial for share i evaluated at x = 0
+			basis := byte(1)
+			for j := range shares {
+				if i != j {
+					basis = gf256Mul(basis, gf256Div(xs[j], xs[j]^xs[i]))
+				}
+			}
+			value ^= gf256Mul(share[pos], basis)
+		}
+		secret[pos] = value
+	}
+
+	return secret, nil
+}
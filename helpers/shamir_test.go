@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitSecret(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	t.Run("reconstructs the secret from any threshold of shares", func(t *testing.T) {
+		shares, err := SplitSecret(secret, 5, 3)
+		assert.NoError(t, err)
+		assert.Len(t, shares, 5)
+
+		for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+			var selected [][]byte
+			for _, i := range subset {
+				selected = append(selected, shares[i])
+			}
+
+			result, err := CombineShares(selected)
+			assert.NoError(t, err)
+			assert.Equal(t, secret, result)
+		}
+	})
+
+	t.Run("does not reconstruct the secret from fewer shares than the threshold", func(t *testing.T) {
+		shares, err := SplitSecret(secret, 5, 3)
+		assert.NoError(t, err)
+
+		result, err := CombineShares(shares[:2])
+		assert.NoError(t, err)
+		assert.NotEqual(t, secret, result)
+	})
+
+	t.Run("returns error for invalid share configuration", func(t *testing.T) {
+		for _, config := range [][2]int{{3, 1}, {2, 3}, {256, 3}} {
+			shares, err := SplitSecret(secret, config[0], config[1])
+
+			assert.Error(t, err)
+			assert.Nil(t, shares)
+		}
+	})
+
+	t.Run("returns error when secret is empty", func(t *testing.T) {
+		shares, err := SplitSecret(nil, 3, 2)
+
+		assert.Nil(t, shares)
+		assert.EqualError(t, err, "secret cannot be empty")
+	})
+}
+
+func TestCombineShares(t *testing.T) {
+	shares, err := SplitSecret([]byte("secret"), 3, 2)
+	assert.NoError(t, err)
+
+	t.Run("returns error when shares are duplicated", func(t *testing.T) {
+		result, err := CombineShares([][]byte{shares[0], shares[0]})
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "shares must have distinct, non-zero x coordinates")
+	})
+
+	t.Run("returns error when shares have different lengths", func(t *testing.T) {
+		result, err := CombineShares([][]byte{shares[0], shares[1][1:]})
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "shares must have the same length")
+	})
+
+	t.Run("returns error when a single share is given", func(t *testing.T) {
+		result, err := CombineShares(shares[:1])
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "at least two shares are required")
+	})
+}
@@ -0,0 +1,125 @@
+package helpers
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// StreamChunkSize is the size of the plaintext chunks a stream is split into before encryption.
+const StreamChunkSize = 64 * 1024
+
+// StreamNonceSuffixSize is the size of the part of each chunk nonce that is not random: a 4 byte big endian chunk
+// counter followed by a byte flagging the last chunk. The rest of the nonce is a random prefix shared by all chunks.
+const StreamNonceSuffixSize = 5
+
+var (
+	ErrStreamFinished   = errors.New("stream has already been finished by its last chunk")
+	ErrStreamTooLong    = errors.New("stream has too many chunks")
+	ErrStreamAuthFailed = errors.New("stream chunk failed authentication, it was modified, reordered or truncated")
+)
+
+// StreamCipher encrypts or decrypts a stream chunk by chunk with an AEAD, in the style of the STREAM construction:
+// every chunk is sealed with a nonce made of the random prefix, the chunk's position and whether it is the last one,
+// so chunks cannot be reordered, dropped or appended, and a stream cannot be truncated at a chunk boundary.
+type StreamCipher struct {
+	aead        cipher.AEAD
+	noncePrefix []byte
+	counter     uint32
+	finished    bool
+}
+
+// NewStreamCipher creates a stream cipher with the given nonce prefix, which must be unique for the AEAD key and
+// StreamNonceSuffixSize bytes shorter than the AEAD's nonce.
+func NewStreamCipher(aead cipher.AEAD, noncePrefix []byte) (*StreamCipher, error) {
+	if len(noncePrefix) != aead.NonceSize()-StreamNonceSuffixSize {
+		return nil, fmt.Errorf("nonce prefix must be %d bytes", aead.NonceSize()-StreamNonceSuffixSize)
+	}
+	return &StreamCipher{aead: aead, noncePrefix: noncePrefix}, nil
+}
+
+// Seal encrypts the next chunk of the stream.
+func (s *StreamCipher) Seal(chunk []byte, last bool, additionalData []byte) ([]byte, error) {
+	nonce, err := s.nextNonce(last)
+	if err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nil, nonce, chunk, additionalData), nil
+}
+
+// Open decrypts the next chunk of the stream.
+func (s *StreamCipher) Open(chunk []byte, last bool, additionalData []byte) ([]byte, error) {
+	nonce, err := s.nextNonce(last)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := s.aead.Open(nil, nonce, chunk, additionalData)
+	if err != nil {
+		return nil, ErrStreamAuthFailed
+	}
+	return plaintext, nil
+}
+
+// nextNonce builds the nonce of the next chunk and advances the counter.
+func (s *StreamCipher) nextNonce(last bool) ([]byte, error) {
+	if s.finished {
+		return nil, ErrStreamFinished
+	}
+	if s.counter == math.MaxUint32 {
+		return nil, ErrStreamTooLong
+	}
+
+	nonce := make([]byte, len(s.noncePrefix)+StreamNonceSuffixSize)
+	copy(nonce, s.noncePrefix)
+	binary.BigEndian.PutUint32(nonce[len(s.noncePrefix):], s.counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+		s.finished = true
+	}
+	s.counter++
+	return nonce, nil
+}
+
+// SealStream reads the plaintext in chunks of StreamChunkSize, encrypts them and passes each sealed chunk to fn
+// along with its index, without buffering more than two chunks. An empty plaintext produces a single empty last
+// chunk. It returns the plaintext size and the number of chunks.
+func SealStream(r io.Reader, s *StreamCipher, additionalData []byte, fn func(index int, sealed []byte) error) (int64, int, error) {
+	reader := bufio.NewReaderSize(r, StreamChunkSize)
+	chunk := make([]byte, StreamChunkSize)
+
+	var size int64
+	for index := 0; ; index++ {
+		n, err := io.ReadFull(reader, chunk)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return size, index, err
+		}
+
+		// Peek ahead to find out whether this is the last chunk before sealing it
+		last := n < len(chunk)
+		if !last {
+			if _, err := reader.Peek(1); errors.Is(err, io.EOF) {
+				last = true
+			} else if err != nil {
+				return size, index, err
+			}
+		}
+
+		sealed, err := s.Seal(chunk[:n], last, additionalData)
+		if err != nil {
+			return size, index, err
+		}
+		if err := fn(index, sealed); err != nil {
+			return size, index, err
+		}
+
+		size += int64(n)
+		if last {
+			return size, index + 1, nil
+		}
+	}
+}
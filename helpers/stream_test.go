@@ -0,0 +1,95 @@
+package helpers
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStreamCipher(t *testing.T, key []byte, noncePrefix []byte) *StreamCipher {
+	block, err := aes.NewCipher(key)
+	assert.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+
+	s, err := NewStreamCipher(aead, noncePrefix)
+	assert.NoError(t, err)
+	return s
+}
+
+func sealTestStream(t *testing.T, key []byte, noncePrefix []byte, plaintext []byte) [][]byte {
+	var chunks [][]byte
+	size, count, err := SealStream(bytes.NewReader(plaintext), newTestStreamCipher(t, key, noncePrefix), []byte("path"), func(index int, sealed []byte) error {
+		assert.Equal(t, len(chunks), index)
+		chunks = append(chunks, sealed)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(plaintext)), size)
+	assert.Equal(t, len(chunks), count)
+	return chunks
+}
+
+func openTestStream(t *testing.T, key []byte, noncePrefix []byte, chunks [][]byte) ([]byte, error) {
+	s := newTestStreamCipher(t, key, noncePrefix)
+
+	var plaintext []byte
+	for i, chunk := range chunks {
+		opened, err := s.Open(chunk, i == len(chunks)-1, []byte("path"))
+		if err != nil {
+			return nil, err
+		}
+		plaintext = append(plaintext, opened...)
+	}
+	return plaintext, nil
+}
+
+func TestSealStream(t *testing.T) {
+	key := make([]byte, 32)
+	noncePrefix := make([]byte, 7)
+	_, _ = rand.Read(key)
+	_, _ = rand.Read(noncePrefix)
+
+	t.Run("round trips streams of any length", func(t *testing.T) {
+		for _, size := range []int{0, 1, StreamChunkSize - 1, StreamChunkSize, StreamChunkSize + 1, 3 * StreamChunkSize} {
+			plaintext := make([]byte, size)
+			_, _ = rand.Read(plaintext)
+
+			chunks := sealTestStream(t, key, noncePrefix, plaintext)
+			assert.Len(t, chunks, max(1, (size+StreamChunkSize-1)/StreamChunkSize))
+
+			opened, err := openTestStream(t, key, noncePrefix, chunks)
+			assert.NoError(t, err)
+			assert.Equal(t, len(plaintext), len(opened))
+			assert.True(t, bytes.Equal(plaintext, opened))
+		}
+	})
+
+	t.Run("detects reordered, truncated and modified chunks", func(t *testing.T) {
+		plaintext := make([]byte, 3*StreamChunkSize)
+		chunks := sealTestStream(t, key, noncePrefix, plaintext)
+
+		_, err := openTestStream(t, key, noncePrefix, [][]byte{chunks[1], chunks[0], chunks[2]})
+		assert.ErrorIs(t, err, ErrStreamAuthFailed)
+
+		_, err = openTestStream(t, key, noncePrefix, chunks[:2])
+		assert.ErrorIs(t, err, ErrStreamAuthFailed)
+
+		chunks[2][0] ^= 1
+		_, err = openTestStream(t, key, noncePrefix, chunks)
+		assert.ErrorIs(t, err, ErrStreamAuthFailed)
+	})
+
+	t.Run("refuses chunks after the last one", func(t *testing.T) {
+		s := newTestStreamCipher(t, key, noncePrefix)
+
+		_, err := s.Seal([]byte("last"), true, nil)
+		assert.NoError(t, err)
+		_, err = s.Seal([]byte("more"), false, nil)
+		assert.ErrorIs(t, err, ErrStreamFinished)
+	})
+}
@@ -0,0 +1,272 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-secrets/helpers"
+	"strconv"
+	"time"
+)
+
+// AppRoleStoragePrefix is the prefix of the storage keys holding AppRoles, their role ID index and secret IDs.
+const AppRoleStoragePrefix = "sys:approle:"
+
+const roleIDByteLength = 16
+const secretIDByteLength = 32
+
+var (
+	ErrAppRoleNotFound     = errors.New("approle does not exist")
+	ErrInvalidAppRole      = errors.New("invalid approle")
+	ErrInvalidAppRoleLogin = errors.New("invalid role id or secret id")
+)
+
+// AppRole is a named machine identity. Machines log in with its role ID and a secret ID issued for it, and get a
+// token with the role's policies, TTL, max TTL and use limit.
+type AppRole struct {
+	Name               string    `json:"name"`
+	RoleID             string    `json:"role_id"`
+	Policies           []string  `json:"policies,omitempty"`
+	TokenTTL           int       `json:"token_ttl"`
+	TokenMaxTTL        int       `json:"token_max_ttl"`
+	TokenNumUses       int       `json:"token_num_uses,omitempty"`
+	SecretIDTTL        int       `json:"secret_id_ttl,omitempty"`         // Default and maximum TTL of secret IDs, 0 for no expiry
+	SecretIDNumUses    int       `json:"secret_id_num_uses,omitempty"`    // Default and maximum logins per secret ID, 0 for unlimited
+	SecretIDBoundCIDRs []string  `json:"secret_id_bound_cidrs,omitempty"` // Addresses any secret ID of the role may log in from
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// SecretID holds the restrictions of a secret ID issued for an AppRole. The secret ID itself is only stored hashed.
+type SecretID struct {
+	CIDRs     []string  `json:"cidr_list,omitempty"` // Addresses the secret ID may log in from, in addition to the role's
+	NumUses   int       `json:"num_uses,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AppRoleService defines the methods for managing AppRoles, issuing their secret IDs and logging in with them.
+type AppRoleService interface {
+	PutRole(ctx context.Context, role AppRole) (*AppRole, error)
+	GetRole(ctx context.Context, name string) (*AppRole, error)
+	DeleteRole(ctx context.Context, name string) error
+	IssueSecretID(ctx context.Context, name string, ttl time.Duration, secretID SecretID) (string, error)
+	Login(ctx context.Context, roleID string, secretID string, ip string) (*AppRole, error)
+}
+
+type AppRoleServiceImpl struct {
+	Redis RedisService
+}
+
+func NewAppRoleService(redis RedisService) AppRoleService {
+	return &AppRoleServiceImpl{Redis: redis}
+}
+
+// roleKey returns the storage key of the AppRole with the given name.
+func roleKey(name string) string {
+	return AppRoleStoragePrefix + "role:" + name
+}
+
+// roleIDKey returns the storage key mapping a role ID to the name of its AppRole.
+func roleIDKey(roleID string) string {
+	return AppRoleStoragePrefix + "role-id:" + roleID
+}
+
+// secretIDKey returns the storage key of a secret ID issued for the named AppRole, which only contains its hash.
+func secretIDKey(name string, secretID string) string {
+	hash := sha256.Sum256([]byte(secretID))
+	return AppRoleStoragePrefix + "secret-id:" + name + ":" + hex.EncodeToString(hash[:])
+}
+
+// secretIDUsesKey returns the storage key of the logins left for a use-limited secret ID.
+func secretIDUsesKey(key string) string {
+	return key + ":uses"
+}
+
+// randomID returns the hex encoding of the given number of random bytes.
+func randomID(byteLength int) (string, error) {
+	bytes := make([]byte, byteLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// PutRole creates or replaces the AppRole with the given name. A new role gets a random role ID, an existing one
+// keeps its role ID and the secret IDs issued for it.
+func (a *AppRoleServiceImpl) PutRole(ctx context.Context, role AppRole) (*AppRole, error) {
+	if err := helpers.ValidateKeyName(role.Name); err != nil {
+		return nil, ErrInvalidKeyName
+	}
+	if err := helpers.ValidateCIDRs(role.SecretIDBoundCIDRs); err != nil {
+		return nil, errors.Join(ErrInvalidAppRole, err)
+	}
+
+	existing, err := a.GetRole(ctx, role.Name)
+	switch {
+	case err == nil:
+		role.RoleID = existing.RoleID
+		role.CreatedAt = existing.CreatedAt
+	case errors.Is(err, ErrAppRoleNotFound):
+		if role.RoleID, err = randomID(roleIDByteLength); err != nil {
+			return nil, err
+		}
+		role.CreatedAt = time.Now()
+		if err := a.Redis.Set(ctx, roleIDKey(role.RoleID), role.Name, 0); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	data, err := json.Marshal(role)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.Redis.Set(ctx, roleKey(role.Name), string(data), 0); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetRole returns the AppRole with the given name.
+func (a *AppRoleServiceImpl) GetRole(ctx context.Context, name string) (*AppRole, error) {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return nil, ErrAppRoleNotFound
+	}
+
+	data, err := a.Redis.Get(ctx, roleKey(name))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, ErrAppRoleNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var role AppRole
+	if err := json.Unmarshal([]byte(data), &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// DeleteRole removes the AppRole with the given name along with all secret IDs issued for it. Tokens issued
+// through it stay valid until they expire or are revoked.
+func (a *AppRoleServiceImpl) DeleteRole(ctx context.Context, name string) error {
+	role, err := a.GetRole(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	iter, err := a.Redis.NewScanner(ctx, AppRoleStoragePrefix+"secret-id:"+name+":*")
+	if err != nil {
+		return err
+	}
+
+	// Delete the role first, so no secret ID can be used to log in while they are deleted
+	keysToDelete := []string{roleKey(name), roleIDKey(role.RoleID)}
+	for iter.Next(ctx) {
+		keysToDelete = append(keysToDelete, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	pipeline, err := a.Redis.NewPipeline(ctx)
+	if err != nil {
+		return err
+	}
+	for _, key := range keysToDelete {
+		if err := pipeline.Del(ctx, key); err != nil {
+			return err
+		}
+	}
+	_, err = pipeline.Exec(ctx)
+	return err
+}
+
+// IssueSecretID creates a new secret ID for the named AppRole that expires after the TTL, or never when it is 0.
+// The secret ID is returned once and only its hash is stored.
+func (a *AppRoleServiceImpl) IssueSecretID(ctx context.Context, name string, ttl time.Duration, secretID SecretID) (string, error) {
+	if _, err := a.GetRole(ctx, name); err != nil {
+		return "", err
+	}
+	if err := helpers.ValidateCIDRs(secretID.CIDRs); err != nil {
+		return "", errors.Join(ErrInvalidAppRole, err)
+	}
+
+	value, err := randomID(secretIDByteLength)
+	if err != nil {
+		return "", err
+	}
+	secretID.CreatedAt = time.Now()
+
+	data, err := json.Marshal(secretID)
+	if err != nil {
+		return "", err
+	}
+
+	// Write the uses counter first, so a use-limited secret ID never exists without it
+	key := secretIDKey(name, value)
+	if secretID.NumUses > 0 {
+		if err := a.Redis.Set(ctx, secretIDUsesKey(key), strconv.Itoa(secretID.NumUses), ttl); err != nil {
+			return "", err
+		}
+	}
+	if err := a.Redis.Set(ctx, key, string(data), ttl); err != nil {
+		_ = a.Redis.Del(ctx, secretIDUsesKey(key))
+		return "", err
+	}
+	return value, nil
+}
+
+// Login checks a role ID and secret ID presented from the given IP address and returns the AppRole to issue a token
+// for. A use-limited secret ID is consumed and deleted after its last login. Every failure wraps
+// ErrInvalidAppRoleLogin, so callers cannot tell an unknown role ID from a wrong secret ID.
+func (a *AppRoleServiceImpl) Login(ctx context.Context, roleID string, secretID string, ip string) (*AppRole, error) {
+	name, err := a.Redis.Get(ctx, roleIDKey(roleID))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: unknown role id", ErrInvalidAppRoleLogin)
+	} else if err != nil {
+		return nil, err
+	}
+
+	role, err := a.GetRole(ctx, name)
+	if errors.Is(err, ErrAppRoleNotFound) || (err == nil && role.RoleID != roleID) {
+		return nil, fmt.Errorf("%w: unknown role id", ErrInvalidAppRoleLogin)
+	} else if err != nil {
+		return nil, err
+	}
+
+	key := secretIDKey(name, secretID)
+	data, err := a.Redis.Get(ctx, key)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: unknown secret id for approle %s", ErrInvalidAppRoleLogin, name)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var stored SecretID
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return nil, err
+	}
+	if !helpers.CIDRsContain(role.SecretIDBoundCIDRs, ip) || !helpers.CIDRsContain(stored.CIDRs, ip) {
+		return nil, fmt.Errorf("%w: %s is not allowed to log in with approle %s", ErrInvalidAppRoleLogin, ip, name)
+	}
+	if stored.NumUses == 0 {
+		return role, nil
+	}
+
+	remaining, err := a.Redis.IncrementBy(ctx, secretIDUsesKey(key), -1)
+	if errors.Is(err, ErrKeyNotFound) || (err == nil && remaining < 0) {
+		return nil, fmt.Errorf("%w: secret id for approle %s has no uses left", ErrInvalidAppRoleLogin, name)
+	} else if err != nil {
+		return nil, err
+	}
+	if remaining == 0 {
+		_ = a.Redis.Del(ctx, key)
+		_ = a.Redis.Del(ctx, secretIDUsesKey(key))
+	}
+	return role, nil
+}
@@ -0,0 +1,258 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-secrets/helpers"
+	"io"
+	"strings"
+	"time"
+)
+
+// BlobPrefix marks secrets holding binary content. The rest of the value is the encrypted BlobManifest; the content
+// itself is stored in chunks next to it, each encrypted with the manifest's data key.
+const BlobPrefix = EnvelopePrefix + ":blob:"
+
+// blobIDSize is the size of the random ID that keeps the chunks of successive uploads to the same path apart.
+const blobIDSize = 16
+
+var (
+	ErrBlobTooLarge   = errors.New("binary secret exceeds the maximum size")
+	ErrBlobIncomplete = errors.New("binary secret is missing chunks")
+)
+
+// BlobManifest describes a binary secret and holds the key its chunks are encrypted with.
+type BlobManifest struct {
+	ID          string    `json:"id"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Chunks      int       `json:"chunks"`
+	Algorithm   Algorithm `json:"algorithm"`
+	DataKey     []byte    `json:"data_key"`
+	NoncePrefix []byte    `json:"nonce_prefix"`
+}
+
+// BlobService defines the methods for storing binary secrets of any size with streaming chunked encryption.
+type BlobService interface {
+	Put(ctx context.Context, path string, contentType string, body io.Reader, ttl time.Duration) (*BlobManifest, error)
+	Manifest(path string, stored string) (*BlobManifest, error)
+	Stream(ctx context.Context, path string, manifest *BlobManifest, w io.Writer) error
+	Discard(ctx context.Context, path string, stored string) error
+}
+
+// BlobServiceImpl splits binary content into chunks of helpers.StreamChunkSize, encrypts them with a random data key
+// using the configured cipher and stores each chunk as its own entry, so content is never held in memory as a whole.
+type BlobServiceImpl struct {
+	Crypto  CryptoService
+	Redis   RedisService
+	Cipher  *Cipher
+	MaxSize int64
+}
+
+func NewBlobService(crypto CryptoService, redis RedisService, c *Cipher, maxSize int64) BlobService {
+	return &BlobServiceImpl{Crypto: crypto, Redis: redis, Cipher: c, MaxSize: maxSize}
+}
+
+// IsBlob reports whether the stored value is the manifest of a binary secret.
+func IsBlob(value string) bool {
+	return strings.HasPrefix(value, BlobPrefix)
+}
+
+// Put encrypts and stores the content read from body as the binary secret at the secret path, replacing whatever
+// was stored there before. It returns ErrBlobTooLarge, leaving the path untouched, once more than MaxSize bytes are read.
+func (b *BlobServiceImpl) Put(ctx context.Context, path string, contentType string, body io.Reader, ttl time.Duration) (*BlobManifest, error) {
+	namespace, _, err := helpers.ParseSecretPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, blobIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, b.Cipher.KeySize)
+	defer clear(dataKey)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	aead, err := b.Cipher.New(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	noncePrefix := make([]byte, aead.NonceSize()-helpers.StreamNonceSuffixSize)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return nil, err
+	}
+
+	stream, err := helpers.NewStreamCipher(aead, noncePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &BlobManifest{
+		ID:          hex.EncodeToString(id),
+		ContentType: contentType,
+		Algorithm:   b.Cipher.ID,
+		DataKey:     dataKey,
+		NoncePrefix: noncePrefix,
+	}
+
+	// Read one byte past the limit, so content of exactly MaxSize bytes is still accepted
+	limited := &io.LimitedReader{R: body, N: b.MaxSize + 1}
+	size, chunks, err := helpers.SealStream(limited, stream, []byte(path), func(index int, sealed []byte) error {
+		if limited.N == 0 {
+			return ErrBlobTooLarge
+		}
+		return b.Redis.Set(ctx, blobChunkKey(namespace, manifest.ID, index), string(sealed), ttl)
+	})
+	if err != nil {
+		_ = b.deleteChunks(ctx, namespace, manifest.ID, chunks)
+		return nil, err
+	}
+	manifest.Size = size
+	manifest.Chunks = chunks
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		_ = b.deleteChunks(ctx, namespace, manifest.ID, chunks)
+		return nil, err
+	}
+	encrypted, err := b.Crypto.Encrypt(string(data), path)
+	clear(data)
+	if err != nil {
+		_ = b.deleteChunks(ctx, namespace, manifest.ID, chunks)
+		return nil, err
+	}
+
+	previous, err := b.Redis.Get(ctx, path)
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		_ = b.deleteChunks(ctx, namespace, manifest.ID, chunks)
+		return nil, err
+	}
+
+	if err := b.Redis.Set(ctx, path, BlobPrefix+encrypted, ttl); err != nil {
+		_ = b.deleteChunks(ctx, namespace, manifest.ID, chunks)
+		return nil, err
+	}
+
+	// The chunks of a binary secret that was replaced are no longer reachable
+	_ = b.Discard(ctx, path, previous)
+	return manifest, nil
+}
+
+// Manifest decrypts the manifest of the binary secret stored at the path.
+func (b *BlobServiceImpl) Manifest(path string, stored string) (*BlobManifest, error) {
+	if !IsBlob(stored) {
+		return nil, errors.New("value is not a binary secret")
+	}
+
+	data, err := b.Crypto.Decrypt(strings.TrimPrefix(stored, BlobPrefix), path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest BlobManifest
+	if err := json.Unmarshal([]byte(data), &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// Stream decrypts the chunks of the binary secret one at a time and writes them to w, wiping the manifest's data
+// key once the cipher is set up. When a chunk is missing or fails authentication, part of the content may already
+// have been written.
+func (b *BlobServiceImpl) Stream(ctx context.Context, path string, manifest *BlobManifest, w io.Writer) error {
+	namespace, _, err := helpers.ParseSecretPath(path)
+	if err != nil {
+		return err
+	}
+
+	blobCipher, err := CipherByID(manifest.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	aead, err := blobCipher.New(manifest.DataKey)
+	clear(manifest.DataKey)
+	if err != nil {
+		return err
+	}
+
+	stream, err := helpers.NewStreamCipher(aead, manifest.NoncePrefix)
+	if err != nil {
+		return err
+	}
+
+	for index := 0; index < manifest.Chunks; index++ {
+		sealed, err := b.Redis.Get(ctx, blobChunkKey(namespace, manifest.ID, index))
+		if errors.Is(err, ErrKeyNotFound) {
+			return ErrBlobIncomplete
+		} else if err != nil {
+			return err
+		}
+
+		chunk, err := stream.Open([]byte(sealed), index == manifest.Chunks-1, []byte(path))
+		if errors.Is(err, helpers.ErrStreamAuthFailed) {
+			return ErrTampered
+		} else if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Discard deletes the chunks of a binary secret after its manifest was deleted or replaced.
+// Values that are not binary secrets have no chunks, so nothing is deleted for them.
+func (b *BlobServiceImpl) Discard(ctx context.Context, path string, stored string) error {
+	if !IsBlob(stored) {
+		return nil
+	}
+
+	manifest, err := b.Manifest(path, stored)
+	if err != nil {
+		return err
+	}
+	clear(manifest.DataKey)
+
+	namespace, _, err := helpers.ParseSecretPath(path)
+	if err != nil {
+		return err
+	}
+	return b.deleteChunks(ctx, namespace, manifest.ID, manifest.Chunks)
+}
+
+// deleteChunks deletes the first count chunks of a binary secret.
+func (b *BlobServiceImpl) deleteChunks(ctx context.Context, namespace string, id string, count int) error {
+	if count == 0 {
+		return nil
+	}
+
+	pipeline, err := b.Redis.NewPipeline(ctx)
+	if err != nil {
+		return err
+	}
+	for index := 0; index < count; index++ {
+		if err := pipeline.Del(ctx, blobChunkKey(namespace, id, index)); err != nil {
+			pipeline.Discard()
+			return err
+		}
+	}
+	_, err = pipeline.Exec(ctx)
+	return err
+}
+
+// blobChunkKey returns the storage key of a chunk. It lives in the namespace, so it is deleted along with the
+// token's other entries, but outside the secret paths.
+func blobChunkKey(namespace string, id string, index int) string {
+	return fmt.Sprintf("%s:blob:%s:%d", namespace, id, index)
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CanaryStorageKey is the storage key of the canary record, a known value encrypted with the CryptoService.
+const CanaryStorageKey = "sys:canary"
+
+// canaryValue is the plaintext of the canary record.
+const canaryValue = "go-secrets key verification canary"
+
+// ErrCanaryMismatch is returned when the loaded key material cannot decrypt the canary record, meaning the
+// store was written with different key material.
+var ErrCanaryMismatch = errors.New("key material does not match the store, the canary record cannot be decrypted")
+
+// CanaryService defines the methods for checking that the loaded key material matches the store.
+type CanaryService interface {
+	Create(ctx context.Context) error
+	Verify(ctx context.Context) error
+}
+
+type CanaryServiceImpl struct {
+	Crypto CryptoService
+	Redis  RedisService
+}
+
+func NewCanaryService(crypto CryptoService, redis RedisService) CanaryService {
+	return &CanaryServiceImpl{Crypto: crypto, Redis: redis}
+}
+
+// Create encrypts the canary record with the active root key and stores it, unless one has already been stored.
+func (c *CanaryServiceImpl) Create(ctx context.Context) error {
+	encrypted, err := c.Crypto.Encrypt(canaryValue, CanaryStorageKey)
+	if err != nil {
+		return err
+	}
+	_, err = c.Redis.SetNX(ctx, CanaryStorageKey, encrypted, 0)
+	return err
+}
+
+// Verify decrypts the canary record and returns ErrCanaryMismatch if that fails. Stores initialized before the
+// canary existed get one created instead.
+func (c *CanaryServiceImpl) Verify(ctx context.Context) error {
+	encrypted, err := c.Redis.Get(ctx, CanaryStorageKey)
+	if errors.Is(err, ErrKeyNotFound) {
+		return c.Create(ctx)
+	} else if err != nil {
+		return err
+	}
+
+	value, err := c.Crypto.Decrypt(encrypted, CanaryStorageKey)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCanaryMismatch, err)
+	}
+	if value != canaryValue {
+		return ErrCanaryMismatch
+	}
+	return nil
+}
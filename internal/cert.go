@@ -0,0 +1,285 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-secrets/helpers"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CertStoragePrefix is the prefix of the storage keys holding certificate roles and the session tokens of client
+// certificates.
+const CertStoragePrefix = "sys:cert:"
+
+var (
+	ErrCertRoleNotFound = errors.New("certificate role does not exist")
+	ErrInvalidCertRole  = errors.New("invalid certificate role")
+	ErrInvalidCertLogin = errors.New("client certificate is not allowed to log in")
+)
+
+// CertRole maps verified client certificates matching its constraints to the policies, TTL, max TTL and use limit
+// of the tokens they are issued.
+type CertRole struct {
+	Name string `json:"name"`
+	helpers.CertificateConstraints
+	Policies     []string `json:"policies,omitempty"`
+	TokenTTL     int      `json:"token_ttl"`
+	TokenMaxTTL  int      `json:"token_max_ttl"`            // Only applies to tokens issued by logging in
+	TokenNumUses int      `json:"token_num_uses,omitempty"` // Only applies to tokens issued by logging in
+}
+
+// CertAuthService defines the methods for managing certificate roles, logging in with client certificates and
+// authenticating requests by their client certificate alone.
+type CertAuthService interface {
+	PutRole(ctx context.Context, role CertRole) (*CertRole, error)
+	GetRole(ctx context.Context, name string) (*CertRole, error)
+	DeleteRole(ctx context.Context, name string) error
+	Login(ctx context.Context, roleName string, cert *x509.Certificate) (*CertRole, error)
+	SessionToken(ctx context.Context, cert *x509.Certificate, ip string) (*TokenRecord, error)
+}
+
+type CertAuthServiceImpl struct {
+	Redis RedisService
+	Token TokenService
+}
+
+func NewCertAuthService(redis RedisService, token TokenService) CertAuthService {
+	return &CertAuthServiceImpl{Redis: redis, Token: token}
+}
+
+// certRoleKey returns the storage key of the certificate role with the given name.
+func certRoleKey(name string) string {
+	return CertStoragePrefix + "role:" + name
+}
+
+// certSessionKey returns the storage key holding the accessor of the session token of a client certificate.
+func certSessionKey(cert *x509.Certificate) string {
+	fingerprint := sha256.Sum256(cert.Raw)
+	return CertStoragePrefix + "session:" + hex.EncodeToString(fingerprint[:])
+}
+
+// certSessionNamespace returns the namespace of the session tokens of a certificate with a role. It has the length of
+// a token HMAC, so it is elided from logs the same way.
+func certSessionNamespace(roleName string, cert *x509.Certificate) string {
+	fingerprint := sha256.Sum256(cert.Raw)
+	namespace := sha256.Sum256([]byte("go-secrets cert session:" + roleName + ":" + hex.EncodeToString(fingerprint[:])))
+	return hex.EncodeToString(namespace[:])
+}
+
+// CertDisplayName returns the name tokens issued for a client certificate are displayed with: its common name, cut
+// to the maximum display name length.
+func CertDisplayName(cert *x509.Certificate) string {
+	if name := []rune(cert.Subject.CommonName); len(name) > helpers.MaxDisplayNameLength {
+		return string(name[:helpers.MaxDisplayNameLength])
+	} else if len(name) > 0 {
+		return string(name)
+	}
+	return "cert"
+}
+
+// PutRole creates or replaces the certificate role with the given name.
+func (c *CertAuthServiceImpl) PutRole(ctx context.Context, role CertRole) (*CertRole, error) {
+	if err := helpers.ValidateKeyName(role.Name); err != nil {
+		return nil, ErrInvalidKeyName
+	}
+	constraints := [][]string{role.CommonNames, role.OrganizationalUnits, role.DNSNames, role.EmailAddresses, role.URIs}
+	for _, patterns := range constraints {
+		if slices.Contains(patterns, "") {
+			return nil, fmt.Errorf("%w: allowed names cannot be empty", ErrInvalidCertRole)
+		}
+	}
+
+	data, err := json.Marshal(role)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Redis.Set(ctx, certRoleKey(role.Name), string(data), 0); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetRole returns the certificate role with the given name.
+func (c *CertAuthServiceImpl) GetRole(ctx context.Context, name string) (*CertRole, error) {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return nil, ErrCertRoleNotFound
+	}
+
+	data, err := c.Redis.Get(ctx, certRoleKey(name))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, ErrCertRoleNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var role CertRole
+	if err := json.Unmarshal([]byte(data), &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// DeleteRole removes the certificate role with the given name. Tokens issued by logging in with it stay valid until
+// they expire or are revoked, session tokens stop authenticating requests right away.
+func (c *CertAuthServiceImpl) DeleteRole(ctx context.Context, name string) error {
+	if _, err := c.GetRole(ctx, name); err != nil {
+		return err
+	}
+	return c.Redis.Del(ctx, certRoleKey(name))
+}
+
+// Login returns the role to issue a token for to a verified client certificate: the named role if the certificate
+// matches it, or without a name the first role by name the certificate matches. Every rejection wraps
+// ErrInvalidCertLogin.
+func (c *CertAuthServiceImpl) Login(ctx context.Context, roleName string, cert *x509.Certificate) (*CertRole, error) {
+	if roleName != "" {
+		role, err := c.GetRole(ctx, roleName)
+		if errors.Is(err, ErrCertRoleNotFound) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCertLogin, err)
+		} else if err != nil {
+			return nil, err
+		}
+		if !role.Matches(cert) {
+			return nil, fmt.Errorf("%w: certificate %q does not match role %s", ErrInvalidCertLogin, cert.Subject, role.Name)
+		}
+		return role, nil
+	}
+
+	iter, err := c.Redis.NewScanner(ctx, certRoleKey("*"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for iter.Next(ctx) {
+		names = append(names, strings.TrimPrefix(iter.Val(), certRoleKey("")))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	// Roles are tried in a fixed order, so a certificate matching several of them always gets the same one
+	slices.Sort(names)
+	for _, name := range names {
+		role, err := c.GetRole(ctx, name)
+		if errors.Is(err, ErrCertRoleNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if role.Matches(cert) {
+			return role, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: certificate %q matches no role", ErrInvalidCertLogin, cert.Subject)
+}
+
+// SessionToken returns the token authenticating requests made with a verified client certificate and no other
+// credentials. The token is issued for the role the certificate logs in with on its first request, and renewed to its
+// TTL as it is used until the certificate expires. Its secrets live in a namespace derived from the role and the
+// certificate's fingerprint rather than the token. A session whose role no longer exists or matches the certificate
+// is left to expire rather than revoked, so a certificate matching the role again before then gets a new session
+// with the same secrets.
+// Session tokens are never use-limited, since a new one would be issued as soon as the uses ran out.
+func (c *CertAuthServiceImpl) SessionToken(ctx context.Context, cert *x509.Certificate, ip string) (*TokenRecord, error) {
+	sessionKey := certSessionKey(cert)
+
+	accessor, err := c.Redis.Get(ctx, sessionKey)
+	if err == nil {
+		record, err := c.Token.LookupAccessor(ctx, accessor, c.Redis)
+		if err == nil {
+			role, err := c.GetRole(ctx, record.Labels["cert_role"])
+			if err == nil && role.Matches(cert) {
+				if err := c.renewSession(ctx, record); err != nil {
+					return nil, err
+				}
+				return record, nil
+			} else if err != nil && !errors.Is(err, ErrCertRoleNotFound) {
+				return nil, err
+			}
+		} else if !errors.Is(err, ErrTokenNotFound) {
+			return nil, err
+		}
+		if err := c.Redis.Del(ctx, sessionKey); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, ErrKeyNotFound) {
+		return nil, err
+	}
+
+	role, err := c.Login(ctx, "", cert)
+	if err != nil {
+		return nil, err
+	}
+
+	// The session cannot outlive the certificate
+	lifetime := time.Until(cert.NotAfter).Truncate(time.Second)
+	if lifetime <= 0 {
+		return nil, fmt.Errorf("%w: certificate %q has expired", ErrInvalidCertLogin, cert.Subject)
+	}
+	ttl := min(time.Duration(role.TokenTTL)*time.Second, lifetime)
+
+	// The raw token is never handed out, the session key is the only way to the record
+	token, err := c.Token.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	record := TokenRecord{
+		Namespace:   certSessionNamespace(role.Name, cert),
+		DisplayName: CertDisplayName(cert),
+		Labels:      map[string]string{"cert_role": role.Name},
+		Policies:    role.Policies,
+		CreatedAt:   time.Now(),
+		CreatorIP:   ip,
+		TTL:         role.TokenTTL,
+		MaxTTL:      int(lifetime / time.Second),
+	}
+	stored, err := c.Token.StoreToken(ctx, token, ttl, record, c.Redis)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the secrets of an earlier session in the namespace as long as this one
+	if err := expireNamespace(ctx, stored, ttl, c.Redis); err != nil {
+		return nil, err
+	}
+
+	// When concurrent requests issued a session token first, theirs is used. This one shares its namespace, so only
+	// its record is discarded.
+	ok, err := c.Redis.SetNX(ctx, sessionKey, stored.Accessor, lifetime)
+	if err == nil && ok {
+		return stored, nil
+	}
+	_ = c.Redis.Del(ctx, stored.HMAC)
+	_ = c.Redis.Del(ctx, AccessorStoragePrefix+stored.Accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	accessor, err = c.Redis.Get(ctx, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	return c.Token.LookupAccessor(ctx, accessor, c.Redis)
+}
+
+// renewSession extends a session token and its secrets to the TTL it was issued with once half of it has passed, so
+// the token is only renewed every so often rather than on every request.
+func (c *CertAuthServiceImpl) renewSession(ctx context.Context, record *TokenRecord) error {
+	ttl := time.Duration(record.TTL) * time.Second
+	remaining, err := c.Redis.TTL(ctx, record.HMAC)
+	if err != nil {
+		return err
+	}
+	if remaining > ttl/2 {
+		return nil
+	}
+	_, err = c.Token.RenewToken(ctx, record, ttl, c.Redis)
+	return err
+}
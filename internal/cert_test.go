@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"go-secrets/helpers"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTokenService returns a token service with an initialised keyring stored in the given Redis.
+func newTestTokenService(t *testing.T, redis RedisService) TokenService {
+	t.Helper()
+	return NewTokenService(newTestKeyring(t, redis))
+}
+
+func TestSessionToken(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+	tokens := newTestTokenService(t, redis)
+	certs := NewCertAuthService(redis, tokens)
+
+	role := CertRole{
+		Name:                   "ci",
+		CertificateConstraints: helpers.CertificateConstraints{CommonNames: []string{"runner"}},
+		TokenTTL:               60,
+	}
+	_, err := certs.PutRole(ctx, role)
+	require.NoError(t, err)
+
+	cert := &x509.Certificate{
+		Raw:      []byte("runner certificate"),
+		Subject:  pkix.Name{CommonName: "runner"},
+		NotAfter: time.Now().Add(time.Hour),
+	}
+
+	session, err := certs.SessionToken(ctx, cert, "127.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, certSessionNamespace("ci", cert), session.Namespace)
+	assert.InDelta(t, 3600, session.MaxTTL, 1)
+
+	secretPath, err := helpers.FormatSecretPath(session.Namespace, "db")
+	require.NoError(t, err)
+	require.NoError(t, redis.Set(ctx, secretPath, "value", time.Minute))
+
+	t.Run("reuses the session without renewing it before half its TTL", func(t *testing.T) {
+		redis.advance(20 * time.Second)
+
+		reused, err := certs.SessionToken(ctx, cert, "127.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, session.Accessor, reused.Accessor)
+		ttl, _ := redis.TTL(ctx, secretPath)
+		assert.Equal(t, 40*time.Second, ttl)
+	})
+
+	t.Run("renews the session and its secrets after half its TTL", func(t *testing.T) {
+		redis.advance(20 * time.Second)
+
+		renewed, err := certs.SessionToken(ctx, cert, "127.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, session.Accessor, renewed.Accessor)
+		for _, key := range []string{renewed.HMAC, secretPath} {
+			ttl, _ := redis.TTL(ctx, key)
+			assert.Equal(t, time.Minute, ttl)
+		}
+	})
+
+	t.Run("keeps the namespace when the certificate matches the role again", func(t *testing.T) {
+		_, err := certs.PutRole(ctx, CertRole{
+			Name:                   "ci",
+			CertificateConstraints: helpers.CertificateConstraints{CommonNames: []string{"deployer"}},
+			TokenTTL:               60,
+		})
+		require.NoError(t, err)
+		_, err = certs.SessionToken(ctx, cert, "127.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidCertLogin)
+
+		_, err = tokens.LookupAccessor(ctx, session.Accessor, redis)
+		assert.NoError(t, err, "the old session is left to expire")
+
+		_, err = certs.PutRole(ctx, role)
+		require.NoError(t, err)
+		reissued, err := certs.SessionToken(ctx, cert, "127.0.0.1")
+		require.NoError(t, err)
+		assert.NotEqual(t, session.Accessor, reissued.Accessor)
+		assert.Equal(t, session.Namespace, reissued.Namespace)
+
+		value, err := redis.Get(ctx, secretPath)
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("loses the secrets once the session expires", func(t *testing.T) {
+		redis.advance(time.Minute)
+
+		_, err := redis.Get(ctx, secretPath)
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	})
+}
+
+func TestSessionTokenBoundedByCertificate(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+	certs := NewCertAuthService(redis, newTestTokenService(t, redis))
+
+	_, err := certs.PutRole(ctx, CertRole{Name: "ci", TokenTTL: 60})
+	require.NoError(t, err)
+
+	t.Run("expires the session with the certificate", func(t *testing.T) {
+		cert := &x509.Certificate{Raw: []byte("short-lived"), NotAfter: time.Now().Add(30 * time.Second)}
+
+		session, err := certs.SessionToken(ctx, cert, "127.0.0.1")
+		require.NoError(t, err)
+		assert.LessOrEqual(t, session.MaxTTL, 30)
+		ttl, _ := redis.TTL(ctx, session.HMAC)
+		assert.LessOrEqual(t, ttl, 30*time.Second)
+	})
+
+	t.Run("rejects expired certificates", func(t *testing.T) {
+		cert := &x509.Certificate{Raw: []byte("expired"), NotAfter: time.Now().Add(-time.Second)}
+
+		_, err := certs.SessionToken(ctx, cert, "127.0.0.1")
+		assert.ErrorIs(t, err, ErrInvalidCertLogin)
+	})
+}
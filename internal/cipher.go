@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Cipher describes an AEAD algorithm that can encrypt stored values.
+type Cipher struct {
+	ID      Algorithm
+	Name    string
+	KeySize int
+	New     func(key []byte) (cipher.AEAD, error)
+}
+
+var (
+	ciphersMu sync.RWMutex
+	ciphers   = map[Algorithm]*Cipher{}
+)
+
+func init() {
+	RegisterCipher(&Cipher{ID: AlgorithmAES256GCM, Name: "aes256-gcm", KeySize: 32, New: newAESGCM})
+	RegisterCipher(&Cipher{ID: AlgorithmXChaCha20Poly1305, Name: "xchacha20-poly1305", KeySize: chacha20poly1305.KeySize, New: chacha20poly1305.NewX})
+}
+
+// RegisterCipher adds an algorithm to the cipher registry. Its ID is recorded in every envelope it
+// encrypts, so it must never be reused for a different algorithm.
+func RegisterCipher(c *Cipher) {
+	ciphersMu.Lock()
+	defer ciphersMu.Unlock()
+
+	if _, exists := ciphers[c.ID]; exists {
+		panic(fmt.Sprintf("cipher with id %d is already registered", c.ID))
+	}
+	ciphers[c.ID] = c
+}
+
+// CipherByID returns the registered cipher with the given algorithm ID.
+func CipherByID(id Algorithm) (*Cipher, error) {
+	ciphersMu.RLock()
+	defer ciphersMu.RUnlock()
+
+	c, ok := ciphers[id]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %d", id)
+	}
+	return c, nil
+}
+
+// CipherByName returns the registered cipher with the given name.
+func CipherByName(name string) (*Cipher, error) {
+	ciphersMu.RLock()
+	defer ciphersMu.RUnlock()
+
+	for _, c := range ciphers {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported algorithm %q, expected one of %v", name, cipherNames())
+}
+
+// cipherNames lists the names of all registered ciphers. Callers must hold ciphersMu.
+func cipherNames() []string {
+	names := make([]string, 0, len(ciphers))
+	for _, c := range ciphers {
+		names = append(names, c.Name)
+	}
+	sort.Strings(names)
+	return names
+}
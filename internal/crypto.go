@@ -7,120 +7,445 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"go-secrets/config"
+	"fmt"
 	"go-secrets/helpers"
 	"io"
+
+	"golang.org/x/crypto/hkdf"
 )
 
 const NonceSize = 12
 
+// SaltSize is the size of the random salt each secret's encryption key is derived with.
+const SaltSize = 32
+
+// secretKeyPurpose is the HKDF context that separates secret encryption keys from keys derived for other purposes.
+// Only envelopes in EnvelopeFormatV3 are encrypted with keys derived for it.
+const secretKeyPurpose = "go-secrets secret encryption"
+
+// keyWrappingPurpose is the HKDF context of the key-encryption keys that wrap per-secret data keys.
+const keyWrappingPurpose = "go-secrets data key wrapping"
+
+// LegacyKeyVersion identifies secrets encrypted before key versioning, which were keyed by the namespace HMAC itself.
+const LegacyKeyVersion = 0
+
 // CryptoService defines the methods for encrypting and decrypting data.
 type CryptoService interface {
-	Encrypt(plaintext string, token string) (string, error)
-	Decrypt(encrypted string, token string) (string, error)
+	Encrypt(plaintext string, path string) (string, error)
+	Decrypt(encrypted string, path string) (string, error)
+	Reencrypt(encrypted string, path string) (string, bool, error)
+	Rewrap(encrypted string, path string) (string, bool, error)
+	KeyVersion(encrypted string, path string) (int, error)
 	GenerateHMAC(data string) (string, error)
 	ValidateHMAC(data string, hmac string) bool
 }
 
-type CryptoServiceImpl struct{}
+type CryptoServiceImpl struct {
+	Keyring KeyringService
+	Cipher  *Cipher
+}
+
+// NewCryptoService creates a CryptoService that encrypts new values with the given cipher.
+func NewCryptoService(keyring KeyringService, c *Cipher) CryptoService {
+	return &CryptoServiceImpl{Keyring: keyring, Cipher: c}
+}
 
-func NewCryptoService() CryptoService {
-	return &CryptoServiceImpl{}
+// deriveNamespaceKey derives the key shared by every secret in a namespace by applying HMAC with SHA256 using the
+// given root key. Only envelopes before EnvelopeFormatV3 and legacy values are encrypted with it.
+func (c *CryptoServiceImpl) deriveNamespaceKey(rootKey *KeyringKey, namespace string) ([]byte, error) {
+	var key []byte
+	err := rootKey.Use(func(value []byte) error {
+		h := hmac.New(sha256.New, value)
+		h.Write([]byte(namespace))
+		key = h.Sum(nil)[:32]
+		return nil
+	})
+	return key, err
 }
 
-// deriveKey generates a derived key by applying HMAC with SHA256 using the server token and the provided token.
-func (c *CryptoServiceImpl) deriveKey(token string) ([]byte, error) {
-	serverToken, err := config.GetServerToken()
+// deriveKey derives a key of the given size from the root key with HKDF-SHA256, using a random salt and a context
+// made of the key's purpose and the storage path, so no two values share a key.
+func deriveKey(rootKey *KeyringKey, salt []byte, purpose string, path string, size int) ([]byte, error) {
+	info := make([]byte, 0, len(purpose)+1+len(path))
+	info = append(info, purpose...)
+	info = append(info, 0)
+	info = append(info, path...)
+
+	key := make([]byte, size)
+	err := rootKey.Use(func(value []byte) error {
+		_, err := io.ReadFull(hkdf.New(sha256.New, value, salt, info), key)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	h := hmac.New(sha256.New, []byte(serverToken))
-	h.Write([]byte(token))
-	return h.Sum(nil)[:32], nil
+	return key, nil
 }
 
-// Encrypt encrypts the provided value using AES-GCM with a derived key from the given token.
-// The encrypted result is returned as a base64 encoded string.
-func (c *CryptoServiceImpl) Encrypt(value string, token string) (string, error) {
-	key, err := c.deriveKey(token)
+// Encrypt encrypts the provided value stored at the given storage path using the configured cipher and a random
+// data key. The data key is wrapped with a key-encryption key derived from the active root key, a random salt and
+// the path. Both are bound to the path, so the value cannot be decrypted once moved elsewhere. The encrypted result
+// is returned in the envelope format.
+func (c *CryptoServiceImpl) Encrypt(value string, path string) (string, error) {
+	if path == "" {
+		return "", errors.New("storage path cannot be empty")
+	}
+
+	rootKey, err := c.Keyring.ActiveKey()
 	if err != nil {
 		return "", err
 	}
 
-	block, err := aes.NewCipher(key)
-	if err != nil {
+	dataKey := make([]byte, c.Cipher.KeySize)
+	defer clear(dataKey)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
 		return "", err
 	}
 
-	nonce := make([]byte, NonceSize) // GCM standard nonce size
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+	envelope := &Envelope{Format: EnvelopeFormatV4, Algorithm: c.Cipher.ID}
+	if err := c.wrapDataKey(envelope, c.Cipher, rootKey, dataKey, path); err != nil {
 		return "", err
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
+	aead, err := c.Cipher.New(dataKey)
 	if err != nil {
 		return "", err
 	}
 
-	ciphertext := aesGCM.Seal(nil, nonce, []byte(value), nil)
-	encrypted := append(nonce, ciphertext...)
-	return base64.StdEncoding.EncodeToString(encrypted), nil
+	envelope.Nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, envelope.Nonce); err != nil {
+		return "", err
+	}
+
+	envelope.Ciphertext = aead.Seal(nil, envelope.Nonce, []byte(value), envelope.DataAssociatedData(path))
+	return envelope.Encode(), nil
 }
 
-// Decrypt decrypts the provided base64 encoded encrypted string using AES-GCM with a derived key from the given token.
-func (c *CryptoServiceImpl) Decrypt(encrypted string, token string) (string, error) {
-	key, err := c.deriveKey(token)
+// Decrypt decrypts the provided encrypted string stored at the given storage path, either in the envelope format
+// or in the legacy base64 layout. It returns ErrTampered when a path-bound value fails authentication, and
+// ErrClientEncrypted for values encrypted by the client.
+func (c *CryptoServiceImpl) Decrypt(encrypted string, path string) (string, error) {
+	plaintext, _, err := c.decrypt(encrypted, path)
 	if err != nil {
 		return "", err
 	}
+	return string(plaintext), nil
+}
 
-	data, err := base64.StdEncoding.DecodeString(encrypted)
+// Reencrypt re-encrypts the value with the active root key and the configured cipher if it was encrypted with an
+// older key version, another algorithm, or is stored in an older layout. Client encrypted values are left as they are.
+// It reports whether the value changed.
+func (c *CryptoServiceImpl) Reencrypt(encrypted string, path string) (string, bool, error) {
+	if IsClientEncrypted(encrypted) {
+		return encrypted, false, nil
+	}
+
+	plaintext, _, err := c.decrypt(encrypted, path)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	if len(data) < NonceSize {
-		return "", errors.New("invalid encrypted data")
+	rootKey, err := c.Keyring.ActiveKey()
+	if err != nil {
+		return "", false, err
+	}
+	envelope, err := ParseEnvelope(encrypted)
+	if err == nil && envelope.Format == EnvelopeFormatV4 && envelope.KeyVersion == rootKey.Version && envelope.Algorithm == c.Cipher.ID {
+		return encrypted, false, nil
+	}
+
+	reencrypted, err := c.Encrypt(string(plaintext), path)
+	if err != nil {
+		return "", false, err
 	}
+	return reencrypted, true, nil
+}
 
-	nonce := data[:NonceSize]
-	ciphertext := data[NonceSize:]
+// Rewrap moves the value to the active root key. Values with a wrapped data key only have their data key rewrapped,
+// leaving the value ciphertext untouched; anything else is re-encrypted. Client encrypted values are left as they
+// are. It reports whether the value changed.
+func (c *CryptoServiceImpl) Rewrap(encrypted string, path string) (string, bool, error) {
+	if IsClientEncrypted(encrypted) {
+		return encrypted, false, nil
+	}
 
-	block, err := aes.NewCipher(key)
+	envelope, err := ParseEnvelope(encrypted)
+	if err != nil || envelope.Format != EnvelopeFormatV4 || envelope.Algorithm != c.Cipher.ID {
+		return c.Reencrypt(encrypted, path)
+	}
+
+	rootKey, err := c.Keyring.ActiveKey()
 	if err != nil {
-		return "", err
+		return "", false, err
+	}
+	if envelope.KeyVersion == rootKey.Version {
+		return encrypted, false, nil
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
+	dataKey, err := c.unwrapDataKey(envelope, c.Cipher, path)
 	if err != nil {
-		return "", err
+		return "", false, err
+	}
+	defer clear(dataKey)
+	if err := c.wrapDataKey(envelope, c.Cipher, rootKey, dataKey, path); err != nil {
+		return "", false, err
+	}
+	return envelope.Encode(), true, nil
+}
+
+// KeyVersion returns the root key version the value was encrypted with.
+// Values in the envelope format record it; legacy values have to be decrypted to find it.
+// It returns ErrClientEncrypted for values encrypted by the client, which depend on no key version.
+func (c *CryptoServiceImpl) KeyVersion(encrypted string, path string) (int, error) {
+	envelope, err := ParseEnvelope(encrypted)
+	if err == nil {
+		return envelope.KeyVersion, nil
+	} else if !errors.Is(err, ErrNotEnvelope) {
+		return 0, err
 	}
 
-	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
+	_, version, err := c.decryptLegacy(encrypted, path)
+	return version, err
+}
+
+// decrypt decrypts the value and returns the plaintext together with the root key version it was encrypted with.
+func (c *CryptoServiceImpl) decrypt(encrypted string, path string) ([]byte, int, error) {
+	envelope, err := ParseEnvelope(encrypted)
+	if errors.Is(err, ErrNotEnvelope) {
+		return c.decryptLegacy(encrypted, path)
+	} else if err != nil {
+		return nil, 0, err
+	}
+
+	envelopeCipher, err := CipherByID(envelope.Algorithm)
 	if err != nil {
-		return "", err
+		return nil, 0, err
 	}
 
-	return string(plaintext), nil
+	var key []byte
+	aad := envelope.AssociatedData(path)
+	switch {
+	case envelope.Format >= EnvelopeFormatV4:
+		key, err = c.unwrapDataKey(envelope, envelopeCipher, path)
+		if err != nil {
+			return nil, 0, err
+		}
+		aad = envelope.DataAssociatedData(path)
+	case envelope.Format == EnvelopeFormatV3:
+		rootKey, err := c.Keyring.Key(envelope.KeyVersion)
+		if err != nil {
+			return nil, 0, fmt.Errorf("key version %d: %w", envelope.KeyVersion, err)
+		}
+		key, err = deriveKey(rootKey, envelope.Salt, secretKeyPurpose, path, envelopeCipher.KeySize)
+		if err != nil {
+			return nil, 0, err
+		}
+	default:
+		namespace, _, err := helpers.ParseSecretPath(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		rootKey, err := c.Keyring.Key(envelope.KeyVersion)
+		if err != nil {
+			return nil, 0, fmt.Errorf("key version %d: %w", envelope.KeyVersion, err)
+		}
+		key, err = c.deriveNamespaceKey(rootKey, namespace)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// The cipher keeps its own copy of the key, so the derived or unwrapped key can be wiped right away
+	aead, err := envelopeCipher.New(key)
+	clear(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return nil, 0, errors.New("invalid nonce size")
+	}
+
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, aad)
+	if err != nil {
+		if envelope.Format != EnvelopeFormatV1 {
+			return nil, 0, ErrTampered
+		}
+		return nil, 0, err
+	}
+	return plaintext, envelope.KeyVersion, nil
 }
 
-func (c *CryptoServiceImpl) GenerateHMAC(data string) (string, error) {
-	serverToken, err := config.GetServerToken()
+// wrapDataKey encrypts the data key with a key-encryption key derived from the root key, a new random salt and
+// the path, and records the salt, wrapped key and key version in the envelope.
+func (c *CryptoServiceImpl) wrapDataKey(envelope *Envelope, envelopeCipher *Cipher, rootKey *KeyringKey, dataKey []byte, path string) error {
+	salt := make([]byte, SaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+
+	kek, err := deriveKey(rootKey, salt, keyWrappingPurpose, path, envelopeCipher.KeySize)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	hmac, err := helpers.GenerateHMAC(data, serverToken)
+	aead, err := envelopeCipher.New(kek)
+	clear(kek)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
 	}
 
-	return hmac, nil
+	envelope.KeyVersion = rootKey.Version
+	envelope.Salt = salt
+	envelope.WrappedKey = append(nonce, aead.Seal(nil, nonce, dataKey, envelope.AssociatedData(path))...)
+	return nil
+}
+
+// unwrapDataKey decrypts the data key of the envelope with the key-encryption key of its root key version.
+// It returns ErrTampered when the wrapped key fails authentication.
+func (c *CryptoServiceImpl) unwrapDataKey(envelope *Envelope, envelopeCipher *Cipher, path string) ([]byte, error) {
+	rootKey, err := c.Keyring.Key(envelope.KeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("key version %d: %w", envelope.KeyVersion, err)
+	}
+
+	kek, err := deriveKey(rootKey, envelope.Salt, keyWrappingPurpose, path, envelopeCipher.KeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := envelopeCipher.New(kek)
+	clear(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.WrappedKey) < aead.NonceSize() {
+		return nil, errors.New("invalid wrapped key")
+	}
+
+	nonce, wrapped := envelope.WrappedKey[:aead.NonceSize()], envelope.WrappedKey[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, wrapped, envelope.AssociatedData(path))
+	if err != nil {
+		return nil, ErrTampered
+	}
+	return dataKey, nil
+}
+
+// decryptLegacy decrypts a value in the legacy base64(nonce || ciphertext) layout, which does not record its
+// key version. It tries every root key version in the keyring, active first, and finally the pre-versioning key.
+func (c *CryptoServiceImpl) decryptLegacy(encrypted string, path string) ([]byte, int, error) {
+	namespace, _, err := helpers.ParseSecretPath(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rootKeys, err := c.Keyring.Keys()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, rootKey := range rootKeys {
+		key, err := c.deriveNamespaceKey(rootKey, namespace)
+		if err != nil {
+			return nil, 0, err
+		}
+		plaintext, err := openAESGCM(key, data)
+		clear(key)
+		if err == nil {
+			return plaintext, rootKey.Version, nil
+		}
+	}
+
+	if legacyKey, err := hex.DecodeString(namespace); err == nil && len(legacyKey) == 32 {
+		if plaintext, err := openAESGCM(legacyKey, data); err == nil {
+			return plaintext, LegacyKeyVersion, nil
+		}
+	}
+
+	return nil, 0, errors.New("could not decrypt with any key version")
+}
+
+// GenerateHMAC computes the HMAC of the data using the active root key.
+func (c *CryptoServiceImpl) GenerateHMAC(data string) (string, error) {
+	rootKey, err := c.Keyring.ActiveKey()
+	if err != nil {
+		return "", err
+	}
+	return hmacWithKey(data, rootKey)
 }
 
 func (c *CryptoServiceImpl) ValidateHMAC(data string, receivedHMAC string) bool {
 	expectedHMAC, _ := c.GenerateHMAC(data)
 	return hmac.Equal([]byte(expectedHMAC), []byte(receivedHMAC))
 }
+
+// hmacWithKey computes the hex encoded HMAC of the data using the hex encoded root key as the HMAC key, matching
+// helpers.GenerateHMAC without leaving the encoded key behind in an immutable string.
+func hmacWithKey(data string, rootKey *KeyringKey) (string, error) {
+	var sum []byte
+	err := rootKey.Use(func(value []byte) error {
+		encodedKey := make([]byte, hex.EncodedLen(len(value)))
+		defer clear(encodedKey)
+		hex.Encode(encodedKey, value)
+
+		h := hmac.New(sha256.New, encodedKey)
+		h.Write([]byte(data))
+		sum = h.Sum(nil)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// newAESGCM creates an AES-GCM cipher using the given key.
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealAESGCM encrypts the plaintext using AES-GCM with the given key and a random nonce.
+// The result is laid out as nonce || ciphertext.
+func sealAESGCM(key []byte, plaintext []byte) ([]byte, error) {
+	aesGCM, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, NonceSize) // GCM standard nonce size
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	ciphertext := aesGCM.Seal(nil, nonce, plaintext, nil)
+	return append(nonce, ciphertext...), nil
+}
+
+// openAESGCM decrypts data laid out as nonce || ciphertext using AES-GCM with the given key.
+func openAESGCM(key []byte, data []byte) ([]byte, error) {
+	if len(data) < NonceSize {
+		return nil, errors.New("invalid encrypted data")
+	}
+
+	aesGCM, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aesGCM.Open(nil, data[:NonceSize], data[NonceSize:], nil)
+}
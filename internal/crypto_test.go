@@ -0,0 +1,300 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"go-secrets/helpers"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestKeyring returns a keyring initialised with a random master key and stored in the given Redis.
+func newTestKeyring(t *testing.T, redis RedisService) KeyringService {
+	t.Helper()
+
+	masterKey := make([]byte, MasterKeySize)
+	_, err := rand.Read(masterKey)
+	require.NoError(t, err)
+
+	keyring := NewKeyringService(redis)
+	require.NoError(t, keyring.Init(context.Background(), masterKey))
+	t.Cleanup(keyring.Unload)
+	return keyring
+}
+
+func newTestCryptoService(t *testing.T) (*CryptoServiceImpl, KeyringService) {
+	t.Helper()
+
+	keyring := newTestKeyring(t, newFakeRedis())
+	cipher, err := CipherByID(AlgorithmAES256GCM)
+	require.NoError(t, err)
+	return NewCryptoService(keyring, cipher).(*CryptoServiceImpl), keyring
+}
+
+func testSecretPath(t *testing.T, namespace string, key string) string {
+	t.Helper()
+
+	path, err := helpers.FormatSecretPath(namespace, key)
+	require.NoError(t, err)
+	return path
+}
+
+// encryptFormat encrypts the plaintext the way values of the given envelope format were written, so older formats
+// can be tested without keeping their encryption code around.
+func encryptFormat(t *testing.T, c *CryptoServiceImpl, format int, plaintext string, path string) string {
+	t.Helper()
+
+	if format == EnvelopeFormatV4 {
+		encrypted, err := c.Encrypt(plaintext, path)
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	rootKey, err := c.Keyring.ActiveKey()
+	require.NoError(t, err)
+	envelope := &Envelope{Format: format, Algorithm: AlgorithmAES256GCM, KeyVersion: rootKey.Version}
+
+	var key []byte
+	if format == EnvelopeFormatV3 {
+		envelope.Salt = make([]byte, SaltSize)
+		_, err = rand.Read(envelope.Salt)
+		require.NoError(t, err)
+		key, err = deriveKey(rootKey, envelope.Salt, secretKeyPurpose, path, 32)
+	} else {
+		namespace, _, parseErr := helpers.ParseSecretPath(path)
+		require.NoError(t, parseErr)
+		key, err = c.deriveNamespaceKey(rootKey, namespace)
+	}
+	require.NoError(t, err)
+
+	aead, err := newAESGCM(key)
+	require.NoError(t, err)
+	envelope.Nonce = make([]byte, aead.NonceSize())
+	_, err = rand.Read(envelope.Nonce)
+	require.NoError(t, err)
+	envelope.Ciphertext = aead.Seal(nil, envelope.Nonce, []byte(plaintext), envelope.AssociatedData(path))
+	return envelope.Encode()
+}
+
+// encryptLegacy encrypts the plaintext in the legacy base64(nonce || ciphertext) layout with the given key.
+func encryptLegacy(t *testing.T, key []byte, plaintext string) string {
+	t.Helper()
+
+	data, err := sealAESGCM(key, []byte(plaintext))
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestEnvelopeEncodeParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		envelope Envelope
+	}{
+		{"v1", Envelope{Format: EnvelopeFormatV1, Algorithm: AlgorithmAES256GCM, KeyVersion: 1, Nonce: []byte("nonce-nonce!"), Ciphertext: []byte("ciphertext")}},
+		{"v2", Envelope{Format: EnvelopeFormatV2, Algorithm: AlgorithmXChaCha20Poly1305, KeyVersion: 2, Nonce: []byte("nonce-nonce!"), Ciphertext: []byte("ciphertext")}},
+		{"v3", Envelope{Format: EnvelopeFormatV3, Algorithm: AlgorithmAES256GCM, KeyVersion: 3, Salt: []byte("salt"), Nonce: []byte("nonce-nonce!"), Ciphertext: []byte("ciphertext")}},
+		{"v4", Envelope{Format: EnvelopeFormatV4, Algorithm: AlgorithmAES256GCM, KeyVersion: 4, Salt: []byte("salt"), WrappedKey: []byte("wrapped"), Nonce: []byte("nonce-nonce!"), Ciphertext: []byte("ciphertext")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := tt.envelope.Encode()
+			assert.True(t, strings.HasPrefix(encoded, "gs:"+tt.name+":"))
+
+			parsed, err := ParseEnvelope(encoded)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.envelope, *parsed)
+		})
+	}
+
+	t.Run("rejects values that are not envelopes", func(t *testing.T) {
+		_, err := ParseEnvelope(base64.StdEncoding.EncodeToString([]byte("nonce-nonce!ciphertext")))
+		assert.ErrorIs(t, err, ErrNotEnvelope)
+		_, err = ParseEnvelope(ClientEncryptedPrefix + "blob")
+		assert.ErrorIs(t, err, ErrClientEncrypted)
+	})
+
+	t.Run("rejects malformed envelopes", func(t *testing.T) {
+		for _, value := range []string{
+			"gs:v5:" + base64.StdEncoding.EncodeToString(make([]byte, 8)),
+			"gs:vx:AA==",
+			"gs:v1:not base64",
+			"gs:v1:" + base64.StdEncoding.EncodeToString([]byte{1, 0, 0}),
+			"gs:v3:" + base64.StdEncoding.EncodeToString([]byte{1, 0, 0, 0, 1, 32, 0}),
+		} {
+			_, err := ParseEnvelope(value)
+			assert.Error(t, err, value)
+		}
+	})
+}
+
+func TestCryptoDecrypt(t *testing.T) {
+	c, _ := newTestCryptoService(t)
+	namespace := strings.Repeat("ab", 32)
+	path := testSecretPath(t, namespace, "db/password")
+
+	rootKey, err := c.Keyring.ActiveKey()
+	require.NoError(t, err)
+	namespaceKey, err := c.deriveNamespaceKey(rootKey, namespace)
+	require.NoError(t, err)
+	preVersioningKey, err := hex.DecodeString(namespace)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		encrypted  string
+		keyVersion int
+	}{
+		{"v1", encryptFormat(t, c, EnvelopeFormatV1, "hunter2", path), rootKey.Version},
+		{"v2", encryptFormat(t, c, EnvelopeFormatV2, "hunter2", path), rootKey.Version},
+		{"v3", encryptFormat(t, c, EnvelopeFormatV3, "hunter2", path), rootKey.Version},
+		{"v4", encryptFormat(t, c, EnvelopeFormatV4, "hunter2", path), rootKey.Version},
+		{"legacy", encryptLegacy(t, namespaceKey, "hunter2"), rootKey.Version},
+		{"legacy before key versioning", encryptLegacy(t, preVersioningKey, "hunter2"), LegacyKeyVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext, err := c.Decrypt(tt.encrypted, path)
+			require.NoError(t, err)
+			assert.Equal(t, "hunter2", plaintext)
+
+			keyVersion, err := c.KeyVersion(tt.encrypted, path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.keyVersion, keyVersion)
+		})
+	}
+
+	t.Run("rejects client encrypted values", func(t *testing.T) {
+		_, err := c.Decrypt(ClientEncryptedPrefix+"blob", path)
+		assert.ErrorIs(t, err, ErrClientEncrypted)
+	})
+}
+
+func TestCryptoTampering(t *testing.T) {
+	c, _ := newTestCryptoService(t)
+	path := testSecretPath(t, "namespace", "db/password")
+
+	for _, format := range []int{EnvelopeFormatV2, EnvelopeFormatV3, EnvelopeFormatV4} {
+		encrypted := encryptFormat(t, c, format, "hunter2", path)
+		name := fmt.Sprintf("v%d", format)
+
+		t.Run(name+" moved to another key", func(t *testing.T) {
+			_, err := c.Decrypt(encrypted, testSecretPath(t, "namespace", "db/other"))
+			assert.ErrorIs(t, err, ErrTampered)
+		})
+
+		t.Run(name+" moved to another namespace", func(t *testing.T) {
+			_, err := c.Decrypt(encrypted, testSecretPath(t, "other", "db/password"))
+			assert.ErrorIs(t, err, ErrTampered)
+		})
+
+		t.Run(name+" with modified ciphertext", func(t *testing.T) {
+			envelope, err := ParseEnvelope(encrypted)
+			require.NoError(t, err)
+			envelope.Ciphertext[0] ^= 1
+
+			_, err = c.Decrypt(envelope.Encode(), path)
+			assert.ErrorIs(t, err, ErrTampered)
+		})
+
+		t.Run(name+" with modified key version", func(t *testing.T) {
+			envelope, err := ParseEnvelope(encrypted)
+			require.NoError(t, err)
+			envelope.KeyVersion++
+
+			_, err = c.Decrypt(envelope.Encode(), path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCryptoPerSecretSalts(t *testing.T) {
+	c, _ := newTestCryptoService(t)
+	path := testSecretPath(t, "namespace", "db/password")
+
+	for _, format := range []int{EnvelopeFormatV3, EnvelopeFormatV4} {
+		first, err := ParseEnvelope(encryptFormat(t, c, format, "hunter2", path))
+		require.NoError(t, err)
+		second, err := ParseEnvelope(encryptFormat(t, c, format, "hunter2", path))
+		require.NoError(t, err)
+
+		assert.Len(t, first.Salt, SaltSize)
+		assert.NotEqual(t, first.Salt, second.Salt)
+		assert.NotEqual(t, first.Ciphertext, second.Ciphertext)
+		if format == EnvelopeFormatV4 {
+			assert.NotEqual(t, first.WrappedKey, second.WrappedKey)
+		}
+	}
+}
+
+func TestCryptoRewrap(t *testing.T) {
+	ctx := context.Background()
+	c, keyring := newTestCryptoService(t)
+	namespace := strings.Repeat("ab", 32)
+	path := testSecretPath(t, namespace, "db/password")
+
+	rootKey, err := c.Keyring.ActiveKey()
+	require.NoError(t, err)
+	namespaceKey, err := c.deriveNamespaceKey(rootKey, namespace)
+	require.NoError(t, err)
+
+	encrypted := encryptFormat(t, c, EnvelopeFormatV4, "hunter2", path)
+	legacy := encryptLegacy(t, namespaceKey, "hunter2")
+	v2 := encryptFormat(t, c, EnvelopeFormatV2, "hunter2", path)
+
+	rotated, err := keyring.Rotate(ctx)
+	require.NoError(t, err)
+
+	t.Run("rewraps the data key and keeps the value ciphertext", func(t *testing.T) {
+		rewrapped, changed, err := c.Rewrap(encrypted, path)
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		before, err := ParseEnvelope(encrypted)
+		require.NoError(t, err)
+		after, err := ParseEnvelope(rewrapped)
+		require.NoError(t, err)
+		assert.Equal(t, rotated.Version, after.KeyVersion)
+		assert.Equal(t, before.Ciphertext, after.Ciphertext)
+		assert.NotEqual(t, before.WrappedKey, after.WrappedKey)
+
+		plaintext, err := c.Decrypt(rewrapped, path)
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", plaintext)
+
+		_, changed, err = c.Rewrap(rewrapped, path)
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("re-encrypts older formats", func(t *testing.T) {
+		for _, value := range []string{legacy, v2} {
+			rewrapped, changed, err := c.Rewrap(value, path)
+			require.NoError(t, err)
+			assert.True(t, changed)
+
+			envelope, err := ParseEnvelope(rewrapped)
+			require.NoError(t, err)
+			assert.Equal(t, EnvelopeFormatV4, envelope.Format)
+			assert.Equal(t, rotated.Version, envelope.KeyVersion)
+
+			plaintext, err := c.Decrypt(rewrapped, path)
+			require.NoError(t, err)
+			assert.Equal(t, "hunter2", plaintext)
+		}
+	})
+
+	t.Run("still decrypts values of the previous key version", func(t *testing.T) {
+		for _, value := range []string{encrypted, legacy, v2} {
+			plaintext, err := c.Decrypt(value, path)
+			require.NoError(t, err)
+			assert.Equal(t, "hunter2", plaintext)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EnvelopePrefix marks values stored in the envelope format. It contains a colon, which never
+// appears in the base64 encoded legacy layout, so both layouts can be told apart unambiguously.
+const EnvelopePrefix = "gs"
+
+// EnvelopeFormatV1 lays the payload out as algorithm (1 byte) || key version (4 bytes, big endian) ||
+// nonce length (1 byte) || nonce || ciphertext.
+const EnvelopeFormatV1 = 1
+
+// EnvelopeFormatV2 uses the same layout as EnvelopeFormatV1, but binds the ciphertext to the envelope
+// header and the value's storage path through AEAD associated data.
+const EnvelopeFormatV2 = 2
+
+// EnvelopeFormatV3 adds a random per-value salt used to derive the value's key with HKDF, laid out as
+// algorithm (1 byte) || key version (4 bytes, big endian) || salt length (1 byte) || salt ||
+// nonce length (1 byte) || nonce || ciphertext. Like EnvelopeFormatV2 it binds the value to its storage path.
+const EnvelopeFormatV3 = 3
+
+// EnvelopeFormatV4 encrypts the value with its own random data key, which is stored wrapped by a key-encryption key
+// derived from the root key like the value key in EnvelopeFormatV3. The payload is laid out as algorithm (1 byte) ||
+// key version (4 bytes, big endian) || salt length (1 byte) || salt || wrapped key length (1 byte) || wrapped key ||
+// nonce length (1 byte) || nonce || ciphertext, where the wrapped key is laid out as nonce || ciphertext.
+const EnvelopeFormatV4 = 4
+
+const envelopeHeaderSize = 6
+
+// ClientEncryptedPrefix marks values encrypted by the client, which the server stores and returns as opaque blobs.
+const ClientEncryptedPrefix = EnvelopePrefix + ":client:"
+
+// Algorithm identifies the cipher a value was encrypted with.
+type Algorithm byte
+
+const (
+	AlgorithmAES256GCM         Algorithm = 1
+	AlgorithmXChaCha20Poly1305 Algorithm = 2
+)
+
+var (
+	ErrNotEnvelope = errors.New("value is not in the envelope format")
+	ErrTampered    = errors.New("ciphertext failed authentication, it was modified or moved from its storage path")
+
+	ErrClientEncrypted = errors.New("value is encrypted by the client and cannot be decrypted by the server")
+)
+
+// Envelope is a self-describing encrypted value, recording everything needed to decrypt it.
+type Envelope struct {
+	Format     int
+	Algorithm  Algorithm
+	KeyVersion int
+	Salt       []byte
+	WrappedKey []byte
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// Encode serializes the envelope as `gs:v<format>:<base64 payload>`.
+func (e *Envelope) Encode() string {
+	payload := make([]byte, 5, envelopeHeaderSize+2+len(e.Salt)+len(e.WrappedKey)+len(e.Nonce)+len(e.Ciphertext))
+	payload[0] = byte(e.Algorithm)
+	binary.BigEndian.PutUint32(payload[1:5], uint32(e.KeyVersion))
+	if e.Format >= EnvelopeFormatV3 {
+		payload = append(payload, byte(len(e.Salt)))
+		payload = append(payload, e.Salt...)
+	}
+	if e.Format >= EnvelopeFormatV4 {
+		payload = append(payload, byte(len(e.WrappedKey)))
+		payload = append(payload, e.WrappedKey...)
+	}
+	payload = append(payload, byte(len(e.Nonce)))
+	payload = append(payload, e.Nonce...)
+	payload = append(payload, e.Ciphertext...)
+
+	return fmt.Sprintf("%s:v%d:%s", EnvelopePrefix, e.Format, base64.StdEncoding.EncodeToString(payload))
+}
+
+// AssociatedData returns the data authenticated alongside the ciphertext of a value stored at the given path.
+// Envelopes in the first format were encrypted without associated data.
+func (e *Envelope) AssociatedData(path string) []byte {
+	if e.Format == EnvelopeFormatV1 {
+		return nil
+	}
+
+	header := fmt.Sprintf("%s:v%d:", EnvelopePrefix, e.Format)
+	aad := make([]byte, 0, len(header)+5+len(path))
+	aad = append(aad, header...)
+	aad = append(aad, byte(e.Algorithm))
+	aad = binary.BigEndian.AppendUint32(aad, uint32(e.KeyVersion))
+	return append(aad, path...)
+}
+
+// DataAssociatedData returns the data authenticated alongside the value ciphertext of an envelope with a wrapped
+// data key. It leaves out the key version, so the data key can be rewrapped without re-encrypting the value.
+func (e *Envelope) DataAssociatedData(path string) []byte {
+	header := fmt.Sprintf("%s:v%d:", EnvelopePrefix, e.Format)
+	aad := make([]byte, 0, len(header)+1+len(path))
+	aad = append(aad, header...)
+	aad = append(aad, byte(e.Algorithm))
+	return append(aad, path...)
+}
+
+// IsEnvelope reports whether the value is in the envelope format rather than the legacy layout.
+func IsEnvelope(value string) bool {
+	return strings.HasPrefix(value, EnvelopePrefix+":")
+}
+
+// IsClientEncrypted reports whether the value was encrypted by the client.
+func IsClientEncrypted(value string) bool {
+	return strings.HasPrefix(value, ClientEncryptedPrefix)
+}
+
+// ParseEnvelope decodes a value in the envelope format.
+// It returns ErrNotEnvelope for values in the legacy base64(nonce || ciphertext) layout.
+func ParseEnvelope(value string) (*Envelope, error) {
+	if !IsEnvelope(value) {
+		return nil, ErrNotEnvelope
+	}
+	if IsClientEncrypted(value) {
+		return nil, ErrClientEncrypted
+	}
+
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[1], "v") {
+		return nil, errors.New("invalid envelope")
+	}
+
+	format, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil {
+		return nil, errors.New("invalid envelope format version")
+	}
+	if format < EnvelopeFormatV1 || format > EnvelopeFormatV4 {
+		return nil, fmt.Errorf("unsupported envelope format version %d", format)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) < envelopeHeaderSize {
+		return nil, errors.New("invalid envelope payload")
+	}
+
+	envelope := &Envelope{
+		Format:     format,
+		Algorithm:  Algorithm(payload[0]),
+		KeyVersion: int(binary.BigEndian.Uint32(payload[1:5])),
+	}
+
+	rest := payload[5:]
+	if format >= EnvelopeFormatV3 {
+		if envelope.Salt, rest, err = readLengthPrefixed(rest); err != nil {
+			return nil, err
+		}
+	}
+	if format >= EnvelopeFormatV4 {
+		if envelope.WrappedKey, rest, err = readLengthPrefixed(rest); err != nil {
+			return nil, err
+		}
+	}
+	if envelope.Nonce, rest, err = readLengthPrefixed(rest); err != nil {
+		return nil, err
+	}
+	envelope.Ciphertext = rest
+
+	return envelope, nil
+}
+
+// readLengthPrefixed splits a field prefixed with its one byte length off the front of the data.
+func readLengthPrefixed(data []byte) ([]byte, []byte, error) {
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return nil, nil, errors.New("invalid envelope payload")
+	}
+	size := int(data[0])
+	return data[1 : 1+size], data[1+size:], nil
+}
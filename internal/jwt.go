@@ -0,0 +1,370 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-secrets/helpers"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// JWTStoragePrefix is the prefix of the storage keys holding JWT issuers and roles.
+const JWTStoragePrefix = "sys:jwt:"
+
+const (
+	jwtLeeway           = 60 * time.Second // Clock skew allowed when checking exp, nbf and iat
+	jwksCacheTTL        = 5 * time.Minute  // How long fetched key sets are used before fetching them again
+	jwksMinRefresh      = 30 * time.Second // How often a JWT with an unknown key ID may trigger fetching the key set
+	maxJWKSSize         = 1 << 20
+	defaultJWTUserClaim = "sub"
+)
+
+var (
+	ErrJWTIssuerNotFound = errors.New("jwt issuer does not exist")
+	ErrJWTRoleNotFound   = errors.New("jwt role does not exist")
+	ErrInvalidJWTConfig  = errors.New("invalid jwt issuer or role")
+	ErrInvalidJWTLogin   = errors.New("invalid or unauthorized jwt")
+)
+
+// JWTIssuer is a trusted JWT issuer and the source of the keys its tokens are verified with, either a local JWKS
+// file or a JWKS URL.
+type JWTIssuer struct {
+	Name     string `json:"name"`
+	Issuer   string `json:"issuer"` // Expected iss claim
+	JWKSURL  string `json:"jwks_url,omitempty"`
+	JWKSFile string `json:"jwks_file,omitempty"`
+}
+
+// JWTRole maps JWTs of an issuer to the policies, TTL, max TTL and use limit of the tokens they are exchanged for.
+// A JWT may only log in with a role when it matches all of the role's bound audiences, subject and claims.
+type JWTRole struct {
+	Name           string              `json:"name"`
+	Issuer         string              `json:"issuer"` // Name of the JWT issuer
+	BoundAudiences []string            `json:"bound_audiences,omitempty"`
+	BoundSubject   string              `json:"bound_subject,omitempty"`
+	BoundClaims    map[string][]string `json:"bound_claims,omitempty"`   // Claim names or JSON pointers to allowed values or globs
+	UserClaim      string              `json:"user_claim"`               // Claim used as the token's display name
+	ClaimMappings  map[string]string   `json:"claim_mappings,omitempty"` // Claim names or JSON pointers to token label keys
+	Policies       []string            `json:"policies,omitempty"`
+	TokenTTL       int                 `json:"token_ttl"`
+	TokenMaxTTL    int                 `json:"token_max_ttl"`
+	TokenNumUses   int                 `json:"token_num_uses,omitempty"`
+}
+
+// JWTAuthService defines the methods for managing JWT issuers and roles, and logging in with JWTs.
+type JWTAuthService interface {
+	PutIssuer(ctx context.Context, issuer JWTIssuer) error
+	GetIssuer(ctx context.Context, name string) (*JWTIssuer, error)
+	DeleteIssuer(ctx context.Context, name string) error
+	PutRole(ctx context.Context, role JWTRole) (*JWTRole, error)
+	GetRole(ctx context.Context, name string) (*JWTRole, error)
+	DeleteRole(ctx context.Context, name string) error
+	Login(ctx context.Context, roleName string, token string) (*JWTRole, *helpers.JWT, error)
+}
+
+// cachedJWKS is a key set loaded for an issuer and when it was loaded.
+type cachedJWKS struct {
+	jwks     *helpers.JWKS
+	loadedAt time.Time
+}
+
+type JWTAuthServiceImpl struct {
+	Redis  RedisService
+	Client *http.Client
+
+	mu      sync.Mutex
+	keySets map[string]cachedJWKS
+}
+
+func NewJWTAuthService(redis RedisService) JWTAuthService {
+	return &JWTAuthServiceImpl{
+		Redis:   redis,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		keySets: make(map[string]cachedJWKS),
+	}
+}
+
+// jwtIssuerKey returns the storage key of the JWT issuer with the given name.
+func jwtIssuerKey(name string) string {
+	return JWTStoragePrefix + "issuer:" + name
+}
+
+// jwtRoleKey returns the storage key of the JWT role with the given name.
+func jwtRoleKey(name string) string {
+	return JWTStoragePrefix + "role:" + name
+}
+
+// PutIssuer creates or replaces the JWT issuer with the given name. Its key set is loaded once to check that it is
+// reachable and holds usable keys.
+func (j *JWTAuthServiceImpl) PutIssuer(ctx context.Context, issuer JWTIssuer) error {
+	if err := helpers.ValidateKeyName(issuer.Name); err != nil {
+		return ErrInvalidKeyName
+	}
+	if issuer.Issuer == "" {
+		return fmt.Errorf("%w: issuer cannot be empty", ErrInvalidJWTConfig)
+	}
+	if (issuer.JWKSURL == "") == (issuer.JWKSFile == "") {
+		return fmt.Errorf("%w: exactly one of jwks_url and jwks_file must be set", ErrInvalidJWTConfig)
+	}
+	if issuer.JWKSURL != "" {
+		parsed, err := url.Parse(issuer.JWKSURL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("%w: jwks_url must be an http or https URL", ErrInvalidJWTConfig)
+		}
+	}
+
+	jwks, err := j.loadJWKS(ctx, &issuer)
+	if err != nil {
+		return errors.Join(ErrInvalidJWTConfig, err)
+	}
+
+	data, err := json.Marshal(issuer)
+	if err != nil {
+		return err
+	}
+	if err := j.Redis.Set(ctx, jwtIssuerKey(issuer.Name), string(data), 0); err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	j.keySets[issuer.Name] = cachedJWKS{jwks: jwks, loadedAt: time.Now()}
+	j.mu.Unlock()
+	return nil
+}
+
+// GetIssuer returns the JWT issuer with the given name.
+func (j *JWTAuthServiceImpl) GetIssuer(ctx context.Context, name string) (*JWTIssuer, error) {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return nil, ErrJWTIssuerNotFound
+	}
+
+	data, err := j.Redis.Get(ctx, jwtIssuerKey(name))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, ErrJWTIssuerNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var issuer JWTIssuer
+	if err := json.Unmarshal([]byte(data), &issuer); err != nil {
+		return nil, err
+	}
+	return &issuer, nil
+}
+
+// DeleteIssuer removes the JWT issuer with the given name. Roles referencing it can no longer be logged in with.
+func (j *JWTAuthServiceImpl) DeleteIssuer(ctx context.Context, name string) error {
+	if _, err := j.GetIssuer(ctx, name); err != nil {
+		return err
+	}
+	if err := j.Redis.Del(ctx, jwtIssuerKey(name)); err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	delete(j.keySets, name)
+	j.mu.Unlock()
+	return nil
+}
+
+// PutRole creates or replaces the JWT role with the given name. Its user claim defaults to sub.
+func (j *JWTAuthServiceImpl) PutRole(ctx context.Context, role JWTRole) (*JWTRole, error) {
+	if err := helpers.ValidateKeyName(role.Name); err != nil {
+		return nil, ErrInvalidKeyName
+	}
+	if _, err := j.GetIssuer(ctx, role.Issuer); errors.Is(err, ErrJWTIssuerNotFound) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJWTConfig, err)
+	} else if err != nil {
+		return nil, err
+	}
+	for claim, label := range role.ClaimMappings {
+		if claim == "" {
+			return nil, fmt.Errorf("%w: claim names cannot be empty", ErrInvalidJWTConfig)
+		}
+		if err := helpers.ValidateKeyName(label); err != nil {
+			return nil, fmt.Errorf("%w: invalid label for claim %q: %v", ErrInvalidJWTConfig, claim, err)
+		}
+	}
+	for claim, values := range role.BoundClaims {
+		if claim == "" || len(values) == 0 {
+			return nil, fmt.Errorf("%w: bound claims need a name and at least one value", ErrInvalidJWTConfig)
+		}
+	}
+	if role.UserClaim == "" {
+		role.UserClaim = defaultJWTUserClaim
+	}
+
+	data, err := json.Marshal(role)
+	if err != nil {
+		return nil, err
+	}
+	if err := j.Redis.Set(ctx, jwtRoleKey(role.Name), string(data), 0); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetRole returns the JWT role with the given name.
+func (j *JWTAuthServiceImpl) GetRole(ctx context.Context, name string) (*JWTRole, error) {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return nil, ErrJWTRoleNotFound
+	}
+
+	data, err := j.Redis.Get(ctx, jwtRoleKey(name))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, ErrJWTRoleNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var role JWTRole
+	if err := json.Unmarshal([]byte(data), &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// DeleteRole removes the JWT role with the given name. Tokens issued through it stay valid until they expire or
+// are revoked.
+func (j *JWTAuthServiceImpl) DeleteRole(ctx context.Context, name string) error {
+	if _, err := j.GetRole(ctx, name); err != nil {
+		return err
+	}
+	return j.Redis.Del(ctx, jwtRoleKey(name))
+}
+
+// Login verifies a JWT against the key set of the role's issuer, checks its issuer, lifetime, audience, subject and
+// bound claims, and returns the role to issue a token for along with the verified JWT. Every rejection wraps
+// ErrInvalidJWTLogin.
+func (j *JWTAuthServiceImpl) Login(ctx context.Context, roleName string, token string) (*JWTRole, *helpers.JWT, error) {
+	role, err := j.GetRole(ctx, roleName)
+	if errors.Is(err, ErrJWTRoleNotFound) {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidJWTLogin, err)
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	issuer, err := j.GetIssuer(ctx, role.Issuer)
+	if errors.Is(err, ErrJWTIssuerNotFound) {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidJWTLogin, err)
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	jwt, err := helpers.ParseJWT(token)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidJWTLogin, err)
+	}
+
+	jwks, err := j.keySet(ctx, issuer, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = jwt.Verify(jwks.Keys)
+	if errors.Is(err, helpers.ErrJWKNotFound) {
+		// The issuer may have rotated its keys since they were loaded
+		if jwks, err = j.keySet(ctx, issuer, true); err != nil {
+			return nil, nil, err
+		}
+		err = jwt.Verify(jwks.Keys)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidJWTLogin, err)
+	}
+
+	if iss, _ := jwt.ClaimString("iss"); iss != issuer.Issuer {
+		return nil, nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidJWTLogin, iss)
+	}
+	if err := jwt.ValidateTime(time.Now(), jwtLeeway); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidJWTLogin, err)
+	}
+	if err := role.match(jwt); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidJWTLogin, err)
+	}
+	return role, jwt, nil
+}
+
+// match checks a verified JWT against the role's bound audiences, subject and claims. A JWT with an audience is only
+// accepted by roles bound to one of its audiences, so tokens meant for other services cannot be used to log in.
+func (r *JWTRole) match(jwt *helpers.JWT) error {
+	audiences := jwt.Audiences()
+	if len(r.BoundAudiences) > 0 || len(audiences) > 0 {
+		if !slices.ContainsFunc(audiences, func(aud string) bool { return slices.Contains(r.BoundAudiences, aud) }) {
+			return fmt.Errorf("audience %v is not bound to role %s", audiences, r.Name)
+		}
+	}
+	if r.BoundSubject != "" {
+		if sub, _ := jwt.ClaimString("sub"); sub != r.BoundSubject {
+			return fmt.Errorf("subject %q is not bound to role %s", sub, r.Name)
+		}
+	}
+	for claim, allowed := range r.BoundClaims {
+		if !jwt.MatchClaim(claim, allowed) {
+			return fmt.Errorf("claim %q does not match role %s", claim, r.Name)
+		}
+	}
+	if _, ok := jwt.ClaimString(r.UserClaim); !ok {
+		return fmt.Errorf("user claim %q is missing", r.UserClaim)
+	}
+	return nil
+}
+
+// keySet returns the cached key set of an issuer, loading it when it is missing or stale. With refresh, the key set
+// is loaded again unless it was loaded very recently.
+func (j *JWTAuthServiceImpl) keySet(ctx context.Context, issuer *JWTIssuer, refresh bool) (*helpers.JWKS, error) {
+	j.mu.Lock()
+	cached, ok := j.keySets[issuer.Name]
+	j.mu.Unlock()
+
+	age := time.Since(cached.loadedAt)
+	if ok && age < jwksCacheTTL && (!refresh || age < jwksMinRefresh) {
+		return cached.jwks, nil
+	}
+
+	jwks, err := j.loadJWKS(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	j.mu.Lock()
+	j.keySets[issuer.Name] = cachedJWKS{jwks: jwks, loadedAt: time.Now()}
+	j.mu.Unlock()
+	return jwks, nil
+}
+
+// loadJWKS reads the key set of an issuer from its JWKS file or fetches it from its JWKS URL.
+func (j *JWTAuthServiceImpl) loadJWKS(ctx context.Context, issuer *JWTIssuer) (*helpers.JWKS, error) {
+	if issuer.JWKSFile != "" {
+		data, err := os.ReadFile(issuer.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwks file: %w", err)
+		}
+		return helpers.ParseJWKS(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer.JWKSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := j.Client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jwks: unexpected status %s", response.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxJWKSSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	return helpers.ParseJWKS(data)
+}
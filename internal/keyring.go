@@ -0,0 +1,457 @@
+package internal
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const KeyringStorageKey = "sys:keyring"
+const MasterKeySize = 32
+const RootKeySize = 32
+
+// keyFingerprintLabel is the message authenticated with a root key to compute its fingerprint.
+const keyFingerprintLabel = "go-secrets key fingerprint"
+
+var (
+	ErrKeyringNotInitialized     = errors.New("keyring is not initialized, run `go-secrets init` first")
+	ErrKeyringAlreadyInitialized = errors.New("keyring is already initialized")
+	ErrKeyringNotLoaded          = errors.New("keyring is not loaded")
+	ErrKeyVersionNotFound        = errors.New("key version does not exist")
+	ErrKeyVersionActive          = errors.New("the active key version cannot be retired")
+	ErrMasterKeyMismatch         = errors.New("could not unseal keyring, the master key does not match")
+)
+
+// KeyringKey is a single versioned root key held in the keyring. Its key material lives in a locked buffer and is
+// only reachable through Use.
+type KeyringKey struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	value     *LockedBuffer
+}
+
+// storedKeyringKey is the serialized form of a KeyringKey inside the sealed keyring.
+type storedKeyringKey struct {
+	Version   int       `json:"version"`
+	Value     []byte    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Keyring holds the root keys used for token hashing and secret encryption.
+// It is persisted in Redis encrypted with the master key.
+type Keyring struct {
+	ActiveVersion int                 `json:"active_version"`
+	Keys          map[int]*KeyringKey `json:"keys"`
+}
+
+// KeyringService defines the methods for initializing, loading and reading the persistent keyring.
+type KeyringService interface {
+	Init(ctx context.Context, masterKey []byte) error
+	Load(ctx context.Context, masterKey []byte) error
+	ActiveKey() (*KeyringKey, error)
+	Key(version int) (*KeyringKey, error)
+	Keys() ([]*KeyringKey, error)
+	Rotate(ctx context.Context) (*KeyringKey, error)
+	Retire(ctx context.Context, version int) error
+	Unload()
+}
+
+type KeyringServiceImpl struct {
+	Redis     RedisService
+	mu        sync.RWMutex
+	keyring   *Keyring
+	masterKey *LockedBuffer
+}
+
+func NewKeyringService(redis RedisService) KeyringService {
+	return &KeyringServiceImpl{Redis: redis}
+}
+
+// Init creates a new keyring with a single root key, seals it with the master key and stores it in Redis.
+// It fails if a keyring has already been stored. On success the master key is moved into a locked buffer and
+// the given slice is overwritten.
+func (k *KeyringServiceImpl) Init(ctx context.Context, masterKey []byte) error {
+	rootKey, err := newKeyringKey(1)
+	if err != nil {
+		return err
+	}
+
+	keyring := &Keyring{
+		ActiveVersion: rootKey.Version,
+		Keys:          map[int]*KeyringKey{rootKey.Version: rootKey},
+	}
+
+	sealed, err := sealKeyring(keyring, masterKey)
+	if err != nil {
+		keyring.destroy()
+		return err
+	}
+
+	stored, err := k.Redis.SetNX(ctx, KeyringStorageKey, sealed, 0)
+	if err == nil && !stored {
+		err = ErrKeyringAlreadyInitialized
+	}
+	if err != nil {
+		keyring.destroy()
+		return err
+	}
+
+	return k.setLoaded(keyring, masterKey)
+}
+
+// Load reads the sealed keyring from Redis and decrypts it with the master key. On success the master key is
+// moved into a locked buffer and the given slice is overwritten.
+func (k *KeyringServiceImpl) Load(ctx context.Context, masterKey []byte) error {
+	sealed, err := k.Redis.Get(ctx, KeyringStorageKey)
+	if errors.Is(err, ErrKeyNotFound) {
+		return ErrKeyringNotInitialized
+	} else if err != nil {
+		return err
+	}
+
+	keyring, err := openKeyring(sealed, masterKey)
+	if err != nil {
+		return err
+	}
+
+	return k.setLoaded(keyring, masterKey)
+}
+
+// Unload drops the keyring from memory and wipes the master key and every root key, so nothing can be decrypted
+// until it is loaded again. Root keys in use by in-flight requests are wiped as soon as those requests are done
+// with them.
+func (k *KeyringServiceImpl) Unload() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.unload()
+}
+
+// setLoaded makes the keyring current, replacing and wiping any keyring loaded before, and takes ownership of the master key.
+func (k *KeyringServiceImpl) setLoaded(keyring *Keyring, masterKey []byte) error {
+	lockedMasterKey, err := NewLockedBufferFrom(masterKey)
+	if err != nil {
+		keyring.destroy()
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.unload()
+	k.keyring = keyring
+	k.masterKey = lockedMasterKey
+	return nil
+}
+
+// unload wipes the master key and the keyring. Callers must hold mu.
+func (k *KeyringServiceImpl) unload() {
+	if k.masterKey != nil {
+		k.masterKey.Destroy()
+		k.masterKey = nil
+	}
+	if k.keyring != nil {
+		k.keyring.destroy()
+		k.keyring = nil
+	}
+}
+
+// ActiveKey returns the root key currently used for new tokens and secrets.
+func (k *KeyringServiceImpl) ActiveKey() (*KeyringKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.keyring == nil {
+		return nil, ErrKeyringNotLoaded
+	}
+
+	key, ok := k.keyring.Keys[k.keyring.ActiveVersion]
+	if !ok {
+		return nil, fmt.Errorf("active key version %d is missing from keyring", k.keyring.ActiveVersion)
+	}
+	return key, nil
+}
+
+// Key returns the root key with the given version.
+func (k *KeyringServiceImpl) Key(version int) (*KeyringKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.keyring == nil {
+		return nil, ErrKeyringNotLoaded
+	}
+
+	key, ok := k.keyring.Keys[version]
+	if !ok {
+		return nil, ErrKeyVersionNotFound
+	}
+	return key, nil
+}
+
+// Keys returns all root keys in the keyring, starting with the active key followed by the rest from newest to oldest.
+func (k *KeyringServiceImpl) Keys() ([]*KeyringKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.keyring == nil {
+		return nil, ErrKeyringNotLoaded
+	}
+
+	keys := make([]*KeyringKey, 0, len(k.keyring.Keys))
+	for _, key := range k.keyring.Keys {
+		keys = append(keys, key)
+	}
+
+	active := k.keyring.ActiveVersion
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Version == active || keys[j].Version == active {
+			return keys[i].Version == active
+		}
+		return keys[i].Version > keys[j].Version
+	})
+	return keys, nil
+}
+
+// Rotate adds a new root key version to the keyring, makes it active and persists the keyring.
+// Older versions stay available for decryption and token lookup until they are retired.
+func (k *KeyringServiceImpl) Rotate(ctx context.Context) (*KeyringKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.keyring == nil {
+		return nil, ErrKeyringNotLoaded
+	}
+
+	latest := 0
+	for version := range k.keyring.Keys {
+		latest = max(latest, version)
+	}
+
+	rootKey, err := newKeyringKey(latest + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring := k.keyring.clone()
+	keyring.Keys[rootKey.Version] = rootKey
+	keyring.ActiveVersion = rootKey.Version
+
+	if err := k.persist(ctx, keyring); err != nil {
+		rootKey.value.Destroy()
+		return nil, err
+	}
+	k.keyring = keyring
+	return rootKey, nil
+}
+
+// Retire removes a root key version from the keyring and persists the keyring.
+// Callers are responsible for making sure nothing in the store still references the version.
+func (k *KeyringServiceImpl) Retire(ctx context.Context, version int) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.keyring == nil {
+		return ErrKeyringNotLoaded
+	}
+	if version == k.keyring.ActiveVersion {
+		return ErrKeyVersionActive
+	}
+	retired, ok := k.keyring.Keys[version]
+	if !ok {
+		return ErrKeyVersionNotFound
+	}
+
+	keyring := k.keyring.clone()
+	delete(keyring.Keys, version)
+
+	if err := k.persist(ctx, keyring); err != nil {
+		return err
+	}
+	k.keyring = keyring
+	retired.value.Destroy()
+	return nil
+}
+
+// persist seals the keyring with the master key and stores it in Redis.
+func (k *KeyringServiceImpl) persist(ctx context.Context, keyring *Keyring) error {
+	var sealed string
+	err := k.masterKey.Use(func(masterKey []byte) error {
+		var err error
+		sealed, err = sealKeyring(keyring, masterKey)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return k.Redis.Set(ctx, KeyringStorageKey, sealed, 0)
+}
+
+// clone returns a copy of the keyring that can be modified without affecting readers of the original.
+func (kr *Keyring) clone() *Keyring {
+	keys := make(map[int]*KeyringKey, len(kr.Keys))
+	for version, key := range kr.Keys {
+		keys[version] = key
+	}
+	return &Keyring{ActiveVersion: kr.ActiveVersion, Keys: keys}
+}
+
+// destroy wipes every root key in the keyring.
+func (kr *Keyring) destroy() {
+	for _, key := range kr.Keys {
+		if key != nil && key.value != nil {
+			key.value.Destroy()
+		}
+	}
+}
+
+// Use calls fn with the key material of the root key, which must not be retained after fn returns.
+// It returns ErrBufferDestroyed once the key has been wiped.
+func (key *KeyringKey) Use(fn func(value []byte) error) error {
+	return key.value.Use(fn)
+}
+
+// Fingerprint identifies the root key without revealing it: the first 8 bytes of an HMAC-SHA256 of a fixed
+// label keyed with the root key, hex encoded.
+func (key *KeyringKey) Fingerprint() (string, error) {
+	var sum []byte
+	err := key.Use(func(value []byte) error {
+		h := hmac.New(sha256.New, value)
+		h.Write([]byte(keyFingerprintLabel))
+		sum = h.Sum(nil)[:8]
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// MarshalJSON serializes the root key including its key material, for sealing the keyring.
+func (key *KeyringKey) MarshalJSON() ([]byte, error) {
+	stored := storedKeyringKey{Version: key.Version, CreatedAt: key.CreatedAt}
+	err := key.Use(func(value []byte) error {
+		stored.Value = append([]byte(nil), value...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer clear(stored.Value)
+
+	return json.Marshal(stored)
+}
+
+// UnmarshalJSON deserializes a root key and moves its key material into a locked buffer.
+func (key *KeyringKey) UnmarshalJSON(data []byte) error {
+	var stored storedKeyringKey
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+
+	value, err := NewLockedBufferFrom(stored.Value)
+	if err != nil {
+		return err
+	}
+	*key = KeyringKey{Version: stored.Version, CreatedAt: stored.CreatedAt, value: value}
+	return nil
+}
+
+// CreateMasterKeyFile generates a random master key and writes it hex encoded to the given path.
+// The file is created with owner-only permissions and must not already exist.
+func CreateMasterKeyFile(path string) ([]byte, error) {
+	masterKey := make([]byte, MasterKeySize)
+	if _, err := rand.Read(masterKey); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not create master key file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(hex.EncodeToString(masterKey) + "\n"); err != nil {
+		return nil, fmt.Errorf("could not write master key file: %w", err)
+	}
+	return masterKey, nil
+}
+
+// ReadMasterKeyFile reads a hex encoded master key from the given path.
+func ReadMasterKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read master key file: %w", err)
+	}
+
+	masterKey, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(masterKey) != MasterKeySize {
+		return nil, errors.New("master key file does not contain a valid key")
+	}
+	clear(data)
+	return masterKey, nil
+}
+
+// newKeyringKey generates a random root key with the given version directly into a locked buffer.
+func newKeyringKey(version int) (*KeyringKey, error) {
+	value, err := NewLockedBuffer(RootKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	err = value.Use(func(data []byte) error {
+		_, err := rand.Read(data)
+		return err
+	})
+	if err != nil {
+		value.Destroy()
+		return nil, err
+	}
+	return &KeyringKey{Version: version, CreatedAt: time.Now().UTC(), value: value}, nil
+}
+
+// sealKeyring serializes the keyring and encrypts it with the master key.
+func sealKeyring(keyring *Keyring, masterKey []byte) (string, error) {
+	data, err := json.Marshal(keyring)
+	if err != nil {
+		return "", err
+	}
+	defer clear(data)
+
+	sealed, err := sealAESGCM(masterKey, data)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openKeyring decrypts a sealed keyring with the master key.
+func openKeyring(sealed string, masterKey []byte) (*Keyring, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := openAESGCM(masterKey, data)
+	if err != nil {
+		return nil, ErrMasterKeyMismatch
+	}
+	defer clear(plaintext)
+
+	var keyring Keyring
+	if err := json.Unmarshal(plaintext, &keyring); err != nil {
+		keyring.destroy()
+		return nil, err
+	}
+	return &keyring, nil
+}
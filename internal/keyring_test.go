@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringRotateAndRetire(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+
+	masterKey := make([]byte, MasterKeySize)
+	_, err := rand.Read(masterKey)
+	require.NoError(t, err)
+	keyring := NewKeyringService(redis)
+	require.NoError(t, keyring.Init(ctx, append([]byte(nil), masterKey...)))
+	t.Cleanup(keyring.Unload)
+
+	// reloaded returns the versions of the keyring stored in Redis
+	reloaded := func(t *testing.T) []int {
+		t.Helper()
+
+		restarted := NewKeyringService(redis)
+		require.NoError(t, restarted.Load(ctx, append([]byte(nil), masterKey...)))
+		defer restarted.Unload()
+		keys, err := restarted.Keys()
+		require.NoError(t, err)
+		versions := make([]int, 0, len(keys))
+		for _, key := range keys {
+			versions = append(versions, key.Version)
+		}
+		return versions
+	}
+
+	t.Run("rotate activates and persists a new version", func(t *testing.T) {
+		for _, expected := range []int{2, 3} {
+			rotated, err := keyring.Rotate(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, expected, rotated.Version)
+
+			active, err := keyring.ActiveKey()
+			require.NoError(t, err)
+			assert.Equal(t, expected, active.Version)
+		}
+
+		// Older versions stay available for decryption
+		_, err := keyring.Key(1)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3, 2, 1}, reloaded(t))
+	})
+
+	t.Run("retire refuses the active version", func(t *testing.T) {
+		assert.ErrorIs(t, keyring.Retire(ctx, 3), ErrKeyVersionActive)
+		assert.ErrorIs(t, keyring.Retire(ctx, 9), ErrKeyVersionNotFound)
+	})
+
+	t.Run("retire removes and persists an older version", func(t *testing.T) {
+		require.NoError(t, keyring.Retire(ctx, 1))
+
+		_, err := keyring.Key(1)
+		assert.ErrorIs(t, err, ErrKeyVersionNotFound)
+		assert.Equal(t, []int{3, 2}, reloaded(t))
+	})
+
+	t.Run("unloaded keyring cannot rotate or retire", func(t *testing.T) {
+		unloaded := NewKeyringService(redis)
+
+		_, err := unloaded.Rotate(ctx)
+		assert.ErrorIs(t, err, ErrKeyringNotLoaded)
+		assert.ErrorIs(t, unloaded.Retire(ctx, 2), ErrKeyringNotLoaded)
+	})
+}
+
+func TestRewrapReferences(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+	keyring := newTestKeyring(t, redis)
+	cipher, err := CipherByID(AlgorithmAES256GCM)
+	require.NoError(t, err)
+	crypto := NewCryptoService(keyring, cipher)
+	tokens := NewTokenService(keyring)
+	rewrap := NewRewrapService(crypto, redis, keyring, NewLogger(slog.LevelError, io.Discard))
+
+	// store writes a token record and one of its secrets with the active key version
+	store := func(t *testing.T) {
+		t.Helper()
+
+		token, err := tokens.GenerateToken()
+		require.NoError(t, err)
+		record, err := tokens.StoreToken(ctx, token, time.Hour, TokenRecord{CreatedAt: time.Now()}, redis)
+		require.NoError(t, err)
+
+		path := testSecretPath(t, record.Namespace, "db")
+		encrypted, err := crypto.Encrypt("hunter2", path)
+		require.NoError(t, err)
+		require.NoError(t, redis.Set(ctx, path, encrypted, time.Hour))
+	}
+
+	store(t)
+	_, err = keyring.Rotate(ctx)
+	require.NoError(t, err)
+	store(t)
+	store(t)
+
+	for version, expected := range map[int]int{1: 2, 2: 4, 3: 0} {
+		references, err := rewrap.References(ctx, version)
+		require.NoError(t, err)
+		assert.Equal(t, expected, references, "version %d", version)
+	}
+}
@@ -51,7 +51,7 @@ func GetLoggerService() (LoggerService, error) {
 	return loggerInstance, nil
 }
 
-// Log logs a message at the specified level, including optional request ID and error details.
+// Log logs a message at the specified level, including optional request ID, token accessor and error details.
 func (l *LoggerServiceImpl) Log(ctx context.Context, level slog.Level, message string, requestID string, err error) {
 	var logAttrs []any
 
@@ -59,6 +59,10 @@ func (l *LoggerServiceImpl) Log(ctx context.Context, level slog.Level, message s
 		logAttrs = append(logAttrs, slog.String("request_id", requestID))
 	}
 
+	if accessor := TokenAccessor(ctx); accessor != "" {
+		logAttrs = append(logAttrs, slog.String("token_accessor", accessor))
+	}
+
 	if err != nil {
 		logAttrs = append(logAttrs, slog.String("error", err.Error()))
 	}
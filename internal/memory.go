@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrBufferDestroyed is returned when key material is used after its buffer was destroyed, for example because
+// the server was sealed while a request was in flight.
+var ErrBufferDestroyed = errors.New("key material has been wiped")
+
+// LockedBuffer holds key material outside the Go heap, in memory that is locked against being swapped out and
+// excluded from core dumps where the platform supports it. Its contents are overwritten when it is destroyed.
+// Reads go through Use, so the buffer is never wiped while the key material is in use.
+type LockedBuffer struct {
+	mu     sync.RWMutex
+	data   []byte
+	locked bool
+}
+
+// NewLockedBuffer allocates a zeroed buffer of the given size. When memory cannot be locked, for example because
+// RLIMIT_MEMLOCK is too low, the buffer is still kept out of core dumps but may be swapped out.
+func NewLockedBuffer(size int) (*LockedBuffer, error) {
+	data, locked, err := allocLocked(size)
+	if err != nil {
+		return nil, err
+	}
+	return &LockedBuffer{data: data, locked: locked}, nil
+}
+
+// NewLockedBufferFrom moves the data into a new locked buffer and overwrites the original.
+func NewLockedBufferFrom(data []byte) (*LockedBuffer, error) {
+	buffer, err := NewLockedBuffer(len(data))
+	if err != nil {
+		return nil, err
+	}
+	copy(buffer.data, data)
+	clear(data)
+	return buffer, nil
+}
+
+// Use calls fn with the contents of the buffer, which must not be retained after fn returns.
+// It returns ErrBufferDestroyed once the buffer has been destroyed.
+func (b *LockedBuffer) Use(fn func(data []byte) error) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.data == nil {
+		return ErrBufferDestroyed
+	}
+	return fn(b.data)
+}
+
+// Locked reports whether the buffer is locked in memory.
+func (b *LockedBuffer) Locked() bool {
+	return b.locked
+}
+
+// Destroy waits until the buffer is no longer in use, then overwrites and releases it. It is safe to call more than once.
+func (b *LockedBuffer) Destroy() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.data == nil {
+		return
+	}
+	clear(b.data)
+	freeLocked(b.data, b.locked)
+	b.data = nil
+}
+
+// CheckMemoryLock reports whether key material can be locked in memory, so operators can be warned at startup.
+func CheckMemoryLock() error {
+	data, locked, err := allocLocked(1)
+	if err != nil {
+		return err
+	}
+	defer freeLocked(data, locked)
+
+	if !locked {
+		return errors.New("memory locking is unavailable, key material may be written to swap; raise RLIMIT_MEMLOCK or grant CAP_IPC_LOCK")
+	}
+	return nil
+}
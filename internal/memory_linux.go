@@ -0,0 +1,26 @@
+//go:build linux
+
+package internal
+
+import "golang.org/x/sys/unix"
+
+// allocLocked maps anonymous memory outside the Go heap, excludes it from core dumps and tries to lock it in memory.
+func allocLocked(size int) ([]byte, bool, error) {
+	data, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANONYMOUS)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := unix.Madvise(data, unix.MADV_DONTDUMP); err != nil {
+		_ = unix.Munmap(data)
+		return nil, false, err
+	}
+	return data, unix.Mlock(data) == nil, nil
+}
+
+// freeLocked unlocks and unmaps memory returned by allocLocked.
+func freeLocked(data []byte, locked bool) {
+	if locked {
+		_ = unix.Munlock(data)
+	}
+	_ = unix.Munmap(data)
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package internal
+
+// allocLocked falls back to heap memory that is only overwritten on release, on platforms without mlock and
+// core dump exclusion support.
+func allocLocked(size int) ([]byte, bool, error) {
+	return make([]byte, size), false, nil
+}
+
+// freeLocked releases memory returned by allocLocked.
+func freeLocked(data []byte, locked bool) {}
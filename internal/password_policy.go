@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-secrets/helpers"
+)
+
+// PasswordPolicyStoragePrefix is the prefix of the storage keys holding named password policies.
+const PasswordPolicyStoragePrefix = "sys:password-policy:"
+
+var (
+	ErrPasswordPolicyNotFound = errors.New("password policy does not exist")
+	ErrInvalidPasswordPolicy  = errors.New("invalid password policy")
+)
+
+// PasswordPolicyService defines the methods for managing named, reusable password policies.
+type PasswordPolicyService interface {
+	Put(ctx context.Context, name string, policy helpers.PasswordPolicy) error
+	Get(ctx context.Context, name string) (helpers.PasswordPolicy, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type PasswordPolicyServiceImpl struct {
+	Redis RedisService
+}
+
+func NewPasswordPolicyService(redis RedisService) PasswordPolicyService {
+	return &PasswordPolicyServiceImpl{Redis: redis}
+}
+
+// Put creates or replaces the password policy with the given name.
+func (p *PasswordPolicyServiceImpl) Put(ctx context.Context, name string, policy helpers.PasswordPolicy) error {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return ErrInvalidKeyName
+	}
+	if err := policy.Validate(); err != nil {
+		return errors.Join(ErrInvalidPasswordPolicy, err)
+	}
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return p.Redis.Set(ctx, PasswordPolicyStoragePrefix+name, string(data), 0)
+}
+
+// Get returns the password policy with the given name.
+func (p *PasswordPolicyServiceImpl) Get(ctx context.Context, name string) (helpers.PasswordPolicy, error) {
+	var policy helpers.PasswordPolicy
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return policy, ErrPasswordPolicyNotFound
+	}
+
+	data, err := p.Redis.Get(ctx, PasswordPolicyStoragePrefix+name)
+	if errors.Is(err, ErrKeyNotFound) {
+		return policy, ErrPasswordPolicyNotFound
+	} else if err != nil {
+		return policy, err
+	}
+
+	err = json.Unmarshal([]byte(data), &policy)
+	return policy, err
+}
+
+// Delete removes the password policy with the given name.
+func (p *PasswordPolicyServiceImpl) Delete(ctx context.Context, name string) error {
+	if _, err := p.Get(ctx, name); err != nil {
+		return err
+	}
+	return p.Redis.Del(ctx, PasswordPolicyStoragePrefix+name)
+}
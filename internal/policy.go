@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-secrets/helpers"
+)
+
+// PolicyStoragePrefix is the prefix of the storage keys holding named token policies.
+const PolicyStoragePrefix = "sys:policy:"
+
+var (
+	ErrPolicyNotFound = errors.New("policy does not exist")
+	ErrInvalidPolicy  = errors.New("invalid policy")
+)
+
+// PolicyService defines the methods for managing named token policies and checking what they allow.
+type PolicyService interface {
+	Put(ctx context.Context, name string, policy helpers.Policy) error
+	Get(ctx context.Context, name string) (helpers.Policy, error)
+	Delete(ctx context.Context, name string) error
+	Allowed(ctx context.Context, names []string, path string, capability string) (bool, error)
+}
+
+type PolicyServiceImpl struct {
+	Redis RedisService
+}
+
+func NewPolicyService(redis RedisService) PolicyService {
+	return &PolicyServiceImpl{Redis: redis}
+}
+
+// Put creates or replaces the policy with the given name.
+func (p *PolicyServiceImpl) Put(ctx context.Context, name string, policy helpers.Policy) error {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return ErrInvalidKeyName
+	}
+	if err := policy.Validate(); err != nil {
+		return errors.Join(ErrInvalidPolicy, err)
+	}
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return p.Redis.Set(ctx, PolicyStoragePrefix+name, string(data), 0)
+}
+
+// Get returns the policy with the given name.
+func (p *PolicyServiceImpl) Get(ctx context.Context, name string) (helpers.Policy, error) {
+	var policy helpers.Policy
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return policy, ErrPolicyNotFound
+	}
+
+	data, err := p.Redis.Get(ctx, PolicyStoragePrefix+name)
+	if errors.Is(err, ErrKeyNotFound) {
+		return policy, ErrPolicyNotFound
+	} else if err != nil {
+		return policy, err
+	}
+
+	err = json.Unmarshal([]byte(data), &policy)
+	return policy, err
+}
+
+// Delete removes the policy with the given name. Tokens it was attached to lose the rights it granted.
+func (p *PolicyServiceImpl) Delete(ctx context.Context, name string) error {
+	if _, err := p.Get(ctx, name); err != nil {
+		return err
+	}
+	return p.Redis.Del(ctx, PolicyStoragePrefix+name)
+}
+
+// Allowed reports whether any of the named policies grants the capability on the path.
+// Policies that no longer exist grant nothing.
+func (p *PolicyServiceImpl) Allowed(ctx context.Context, names []string, path string, capability string) (bool, error) {
+	for _, name := range names {
+		policy, err := p.Get(ctx, name)
+		if errors.Is(err, ErrPolicyNotFound) {
+			continue
+		} else if err != nil {
+			return false, err
+		}
+		if policy.Allows(path, capability) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
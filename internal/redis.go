@@ -2,8 +2,11 @@ package internal
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,9 +16,13 @@ import (
 // RedisService defines the interface for Redis operations.
 type RedisService interface {
 	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+	CompareAndSet(ctx context.Context, key string, oldValue string, newValue string) (bool, error)
 	Get(ctx context.Context, key string) (string, error)
+	IncrementBy(ctx context.Context, key string, delta int64) (int64, error)
 	Del(ctx context.Context, key string) error
 	TTL(ctx context.Context, key string) (time.Duration, error)
+	Expire(ctx context.Context, key string, ttl time.Duration) error
 	NewScanner(ctx context.Context, match string) (RedisScanner, error)
 	NewPipeline(ctx context.Context) (RedisPipeline, error)
 }
@@ -32,6 +39,7 @@ type RedisScannerImpl struct {
 
 type RedisPipeline interface {
 	Del(ctx context.Context, key string) error
+	Expire(ctx context.Context, key string, ttl time.Duration) error
 	Exec(ctx context.Context) ([]RedisResult, error)
 	Discard()
 }
@@ -45,6 +53,40 @@ type RedisResult struct {
 	Err error
 }
 
+// NoExpiry is the TTL reported for keys that never expire.
+const NoExpiry time.Duration = -1
+
+// ErrKeyNotFound is returned by Get when the requested key does not exist.
+var ErrKeyNotFound = errors.New("key does not exist")
+
+// compareAndSetScript replaces a value only if it still matches the expected one, keeping the key's TTL.
+var compareAndSetScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("SET", KEYS[1], ARGV[2], "KEEPTTL")
+end
+return false
+`)
+
+// incrementScript changes a counter only if it exists, so a counter that expired is not recreated without a TTL.
+var incrementScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("INCRBY", KEYS[1], ARGV[1])
+end
+return false
+`)
+
+// logKey returns the key with the token HMACs and namespaces in it elided, so logs and errors never identify a
+// token by them. Tokens are identified in logs by their accessor instead.
+func logKey(key string) string {
+	parts := strings.Split(key, ":")
+	for i, part := range parts {
+		if _, err := hex.DecodeString(part); err == nil && len(part) == 64 {
+			parts[i] = "<token>"
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
 // Redis represents a struct that holds the Redis client for performing Redis operations.
 type RedisServiceImpl struct {
 	Client *redis.Client
@@ -87,15 +129,38 @@ func (r *RedisServiceImpl) Set(ctx context.Context, key string, value string, tt
 	if err != nil {
 		return fmt.Errorf("could not set key: %w", err)
 	}
-	slog.Info("key set in redis", "key", key)
+	slog.Info("key set in redis", "key", logKey(key))
 	return nil
 }
 
+// SetNX stores a value in Redis only if the key does not already exist.
+// It reports whether the value was stored.
+func (r *RedisServiceImpl) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	ok, err := r.Client.SetNX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("could not set key: %w", err)
+	}
+	return ok, nil
+}
+
+// CompareAndSet atomically replaces the value of a key if it still equals oldValue, keeping its TTL.
+// It reports whether the value was replaced.
+func (r *RedisServiceImpl) CompareAndSet(ctx context.Context, key string, oldValue string, newValue string) (bool, error) {
+	err := compareAndSetScript.Run(ctx, r.Client, []string{key}, oldValue, newValue).Err()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not set key: %w", err)
+	}
+	return true, nil
+}
+
 // Get retrieves a value from Redis.
 func (r *RedisServiceImpl) Get(ctx context.Context, key string) (string, error) {
 	value, err := r.Client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return "", fmt.Errorf("key does not exist: %s", key)
+		return "", fmt.Errorf("%w: %s", ErrKeyNotFound, logKey(key))
 	}
 	if err != nil {
 		return "", fmt.Errorf("could not get key: %w", err)
@@ -103,6 +168,18 @@ func (r *RedisServiceImpl) Get(ctx context.Context, key string) (string, error)
 	return value, nil
 }
 
+// IncrementBy atomically adds delta to an existing counter, keeping its TTL, and returns the new value.
+func (r *RedisServiceImpl) IncrementBy(ctx context.Context, key string, delta int64) (int64, error) {
+	value, err := incrementScript.Run(ctx, r.Client, []string{key}, delta).Int64()
+	if err == redis.Nil {
+		return 0, fmt.Errorf("%w: %s", ErrKeyNotFound, logKey(key))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not increment key: %w", err)
+	}
+	return value, nil
+}
+
 // Del removes a key from Redis.
 func (r *RedisServiceImpl) Del(ctx context.Context, key string) error {
 	err := r.Client.Del(ctx, key).Err()
@@ -121,6 +198,15 @@ func (r *RedisServiceImpl) TTL(ctx context.Context, key string) (time.Duration,
 	return ttl, nil
 }
 
+// Expire sets a new TTL on an existing key.
+func (r *RedisServiceImpl) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	err := r.Client.Expire(ctx, key, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("could not set TTL for key: %w", err)
+	}
+	return nil
+}
+
 func (r *RedisServiceImpl) NewScanner(ctx context.Context, match string) (RedisScanner, error) {
 	iter := r.Client.Scan(ctx, 0, match, 100).Iterator()
 	return &RedisScannerImpl{Iterator: iter}, nil
@@ -147,6 +233,10 @@ func (rp *RedisPipelineImpl) Del(ctx context.Context, key string) error {
 	return rp.Pipe.Del(ctx, key).Err()
 }
 
+func (rp *RedisPipelineImpl) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return rp.Pipe.Expire(ctx, key, ttl).Err()
+}
+
 func (rp *RedisPipelineImpl) Exec(ctx context.Context) ([]RedisResult, error) {
 	cmds, err := rp.Pipe.Exec(ctx)
 	if err != nil {
@@ -155,11 +245,16 @@ func (rp *RedisPipelineImpl) Exec(ctx context.Context) ([]RedisResult, error) {
 
 	results := make([]RedisResult, len(cmds))
 	for i, cmd := range cmds {
-		stringCmd, ok := cmd.(*redis.StringCmd)
-		if !ok {
+		switch typedCmd := cmd.(type) {
+		case *redis.StringCmd:
+			results[i] = RedisResult{Val: typedCmd.Val(), Err: typedCmd.Err()}
+		case *redis.IntCmd:
+			results[i] = RedisResult{Val: typedCmd.Val(), Err: typedCmd.Err()}
+		case *redis.BoolCmd:
+			results[i] = RedisResult{Val: typedCmd.Val(), Err: typedCmd.Err()}
+		default:
 			return nil, fmt.Errorf("unexpected command type: %T", cmd)
 		}
-		results[i] = RedisResult{Val: stringCmd.Val(), Err: stringCmd.Err()}
 	}
 
 	return results, nil
@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeRedis is an in-memory RedisService for tests. Its clock can be moved forward to expire keys.
+type fakeRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expiry  map[string]time.Time
+	elapsed time.Duration
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: map[string]string{}, expiry: map[string]time.Time{}}
+}
+
+// advance moves the clock forward, expiring the keys whose TTL has passed.
+func (f *fakeRedis) advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.elapsed += d
+}
+
+func (f *fakeRedis) now() time.Time {
+	return time.Now().Add(f.elapsed)
+}
+
+// exists reports whether the key exists, deleting it once it has expired.
+func (f *fakeRedis) exists(key string) bool {
+	if _, ok := f.values[key]; !ok {
+		return false
+	}
+	if expiry, ok := f.expiry[key]; ok && !f.now().Before(expiry) {
+		delete(f.values, key)
+		delete(f.expiry, key)
+		return false
+	}
+	return true
+}
+
+func (f *fakeRedis) set(key string, value string, ttl time.Duration) {
+	f.values[key] = value
+	delete(f.expiry, key)
+	if ttl > 0 {
+		f.expiry[key] = f.now().Add(ttl)
+	}
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.set(key, value, ttl)
+	return nil
+}
+
+func (f *fakeRedis) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.exists(key) {
+		return false, nil
+	}
+	f.set(key, value, ttl)
+	return true, nil
+}
+
+func (f *fakeRedis) CompareAndSet(ctx context.Context, key string, oldValue string, newValue string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.exists(key) || f.values[key] != oldValue {
+		return false, nil
+	}
+	f.values[key] = newValue
+	return true, nil
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.exists(key) {
+		return "", fmt.Errorf("%w: %s", ErrKeyNotFound, logKey(key))
+	}
+	return f.values[key], nil
+}
+
+func (f *fakeRedis) IncrementBy(ctx context.Context, key string, delta int64) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.exists(key) {
+		return 0, fmt.Errorf("%w: %s", ErrKeyNotFound, logKey(key))
+	}
+	value, err := strconv.ParseInt(f.values[key], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	value += delta
+	f.values[key] = strconv.FormatInt(value, 10)
+	return value, nil
+}
+
+func (f *fakeRedis) Del(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.values, key)
+	delete(f.expiry, key)
+	return nil
+}
+
+// TTL reports -2 for missing keys and NoExpiry for keys without a TTL, like Redis.
+func (f *fakeRedis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.exists(key) {
+		return -2, nil
+	}
+	expiry, ok := f.expiry[key]
+	if !ok {
+		return NoExpiry, nil
+	}
+	return expiry.Sub(f.now()).Round(time.Second), nil
+}
+
+func (f *fakeRedis) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.exists(key) {
+		f.expiry[key] = f.now().Add(ttl)
+	}
+	return nil
+}
+
+func (f *fakeRedis) NewScanner(ctx context.Context, match string) (RedisScanner, error) {
+	pattern := regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(match), `\*`, ".*") + "$")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	scanner := &fakeScanner{}
+	for key := range f.values {
+		if f.exists(key) && pattern.MatchString(key) {
+			scanner.keys = append(scanner.keys, key)
+		}
+	}
+	sort.Strings(scanner.keys)
+	return scanner, nil
+}
+
+func (f *fakeRedis) NewPipeline(ctx context.Context) (RedisPipeline, error) {
+	return &fakePipeline{redis: f}, nil
+}
+
+// keys returns the existing keys with the given prefix.
+func (f *fakeRedis) keys(prefix string) []string {
+	scanner, _ := f.NewScanner(context.Background(), prefix+"*")
+	return scanner.(*fakeScanner).keys
+}
+
+type fakeScanner struct {
+	keys []string
+	next int
+}
+
+func (s *fakeScanner) Next(ctx context.Context) bool {
+	s.next++
+	return s.next <= len(s.keys)
+}
+
+func (s *fakeScanner) Val() string {
+	return s.keys[s.next-1]
+}
+
+func (s *fakeScanner) Err() error {
+	return nil
+}
+
+// fakePipeline queues commands and runs them on Exec.
+type fakePipeline struct {
+	redis    *fakeRedis
+	commands []func() error
+}
+
+func (p *fakePipeline) Del(ctx context.Context, key string) error {
+	p.commands = append(p.commands, func() error { return p.redis.Del(ctx, key) })
+	return nil
+}
+
+func (p *fakePipeline) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	p.commands = append(p.commands, func() error { return p.redis.Expire(ctx, key, ttl) })
+	return nil
+}
+
+func (p *fakePipeline) Exec(ctx context.Context) ([]RedisResult, error) {
+	results := make([]RedisResult, len(p.commands))
+	for i, command := range p.commands {
+		results[i] = RedisResult{Err: command()}
+	}
+	p.commands = nil
+	return results, nil
+}
+
+func (p *fakePipeline) Discard() {
+	p.commands = nil
+}
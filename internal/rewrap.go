@@ -0,0 +1,260 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-secrets/helpers"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rewrapProgressInterval = 1000
+
+// encryptedStoragePrefixes lists the prefixes of system entries encrypted with the CryptoService, which the
+// rewrap job moves to the active key along with secrets.
+var encryptedStoragePrefixes = []string{TransitStoragePrefix, SigningStoragePrefix, CanaryStorageKey}
+
+// RewrapStatus reports the progress of the background job that moves stored data to the active key version.
+type RewrapStatus struct {
+	Running       bool
+	TargetVersion int
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	Scanned       int
+	Reencrypted   int
+	Failed        int
+	PendingTokens map[int]int
+	Error         string
+}
+
+// RewrapService defines the methods for re-encrypting stored data with the active key version.
+type RewrapService interface {
+	Start() (bool, error)
+	Status() RewrapStatus
+	References(ctx context.Context, version int) (int, error)
+}
+
+// RewrapServiceImpl scans the store in the background, re-encrypting secrets with the active root key.
+// Token records can only be re-hashed when their raw token is presented, which happens on their next
+// authenticated request; the job counts the records still hashed with older versions instead.
+type RewrapServiceImpl struct {
+	Crypto  CryptoService
+	Redis   RedisService
+	Keyring KeyringService
+	Logger  LoggerService
+	mu      sync.Mutex
+	status  RewrapStatus
+}
+
+func NewRewrapService(crypto CryptoService, redis RedisService, keyring KeyringService, logger LoggerService) RewrapService {
+	return &RewrapServiceImpl{Crypto: crypto, Redis: redis, Keyring: keyring, Logger: logger}
+}
+
+// Start launches the re-encryption job targeting the active key version.
+// It reports false if a job is already running.
+func (rw *RewrapServiceImpl) Start() (bool, error) {
+	rootKey, err := rw.Keyring.ActiveKey()
+	if err != nil {
+		return false, err
+	}
+
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.status.Running {
+		return false, nil
+	}
+
+	rw.status = RewrapStatus{
+		Running:       true,
+		TargetVersion: rootKey.Version,
+		StartedAt:     time.Now().UTC(),
+		PendingTokens: map[int]int{},
+	}
+	go rw.run(context.Background(), rootKey.Version)
+	return true, nil
+}
+
+// Status returns a snapshot of the current or last re-encryption job.
+func (rw *RewrapServiceImpl) Status() RewrapStatus {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	status := rw.status
+	status.PendingTokens = maps.Clone(rw.status.PendingTokens)
+	return status
+}
+
+// References counts the token records, secrets and encrypted system entries in the store that still depend on the given key version.
+func (rw *RewrapServiceImpl) References(ctx context.Context, version int) (int, error) {
+	references := 0
+	err := rw.scan(ctx, func(key string) error {
+		keyVersion, err := rw.keyVersion(ctx, key)
+		if errors.Is(err, ErrKeyNotFound) || errors.Is(err, ErrClientEncrypted) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if keyVersion == version {
+			references++
+		}
+		return nil
+	})
+	return references, err
+}
+
+// run re-encrypts every secret and encrypted system entry that is not yet encrypted with the target version.
+func (rw *RewrapServiceImpl) run(ctx context.Context, target int) {
+	rw.Logger.Log(ctx, slog.LevelInfo, fmt.Sprintf("rewrap started for key version %d", target), "", nil)
+
+	err := rw.scan(ctx, func(key string) error {
+		if isTokenRecordKey(key) {
+			rw.countToken(ctx, key, target)
+		} else {
+			rw.reencryptSecret(ctx, key)
+		}
+
+		status := rw.Status()
+		if status.Scanned%rewrapProgressInterval == 0 {
+			message := fmt.Sprintf("rewrap progress: scanned %d, re-encrypted %d, failed %d", status.Scanned, status.Reencrypted, status.Failed)
+			rw.Logger.Log(ctx, slog.LevelInfo, message, "", nil)
+		}
+		return nil
+	})
+	if err != nil {
+		rw.Logger.LogError(ctx, "rewrap failed", "", err)
+	}
+
+	rw.mu.Lock()
+	rw.status.Running = false
+	rw.status.FinishedAt = time.Now().UTC()
+	if err != nil {
+		rw.status.Error = err.Error()
+	}
+	status := rw.status
+	rw.mu.Unlock()
+
+	message := fmt.Sprintf("rewrap finished: scanned %d, re-encrypted %d, failed %d", status.Scanned, status.Reencrypted, status.Failed)
+	rw.Logger.Log(ctx, slog.LevelInfo, message, "", nil)
+}
+
+// reencryptSecret moves a single secret to the active key, unless it changed in the meantime. Secrets with a wrapped
+// data key only have their data key rewrapped.
+func (rw *RewrapServiceImpl) reencryptSecret(ctx context.Context, key string) {
+	defer rw.update(func(s *RewrapStatus) { s.Scanned++ })
+
+	value, err := rw.Redis.Get(ctx, key)
+	if errors.Is(err, ErrKeyNotFound) {
+		return
+	} else if err != nil {
+		rw.Logger.LogWarn(ctx, "rewrap could not read secret", "", err)
+		rw.update(func(s *RewrapStatus) { s.Failed++ })
+		return
+	}
+
+	marker, encrypted := splitStoredValue(value)
+	reencrypted, changed, err := rw.Crypto.Rewrap(encrypted, key)
+	if err != nil {
+		rw.Logger.LogWarn(ctx, "rewrap could not re-encrypt secret", "", err)
+		rw.update(func(s *RewrapStatus) { s.Failed++ })
+		return
+	}
+	if !changed {
+		return
+	}
+
+	replaced, err := rw.Redis.CompareAndSet(ctx, key, value, marker+reencrypted)
+	if err != nil {
+		rw.Logger.LogWarn(ctx, "rewrap could not store secret", "", err)
+		rw.update(func(s *RewrapStatus) { s.Failed++ })
+		return
+	}
+	if replaced {
+		rw.update(func(s *RewrapStatus) { s.Reencrypted++ })
+	}
+}
+
+// countToken records a token record that is still hashed with an older key version.
+func (rw *RewrapServiceImpl) countToken(ctx context.Context, key string, target int) {
+	defer rw.update(func(s *RewrapStatus) { s.Scanned++ })
+
+	keyVersion, err := rw.keyVersion(ctx, key)
+	if err != nil || keyVersion == target {
+		return
+	}
+	rw.update(func(s *RewrapStatus) { s.PendingTokens[keyVersion]++ })
+}
+
+// keyVersion returns the root key version a token record or secret depends on.
+func (rw *RewrapServiceImpl) keyVersion(ctx context.Context, key string) (int, error) {
+	value, err := rw.Redis.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	if isTokenRecordKey(key) {
+		record, err := ParseTokenRecord(key, value)
+		if err != nil {
+			return 0, err
+		}
+		return record.KeyVersion, nil
+	}
+
+	_, encrypted := splitStoredValue(value)
+	return rw.Crypto.KeyVersion(encrypted, key)
+}
+
+// scan calls fn for every token record, secret and encrypted system entry in the store.
+func (rw *RewrapServiceImpl) scan(ctx context.Context, fn func(key string) error) error {
+	iter, err := rw.Redis.NewScanner(ctx, "*")
+	if err != nil {
+		return err
+	}
+
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if !isTokenRecordKey(key) && !isEncryptedKey(key) {
+			continue
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+func (rw *RewrapServiceImpl) update(fn func(s *RewrapStatus)) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	fn(&rw.status)
+}
+
+// splitStoredValue separates the marker of a binary secret's manifest from the encrypted value following it.
+func splitStoredValue(value string) (string, string) {
+	if IsBlob(value) {
+		return BlobPrefix, strings.TrimPrefix(value, BlobPrefix)
+	}
+	return "", value
+}
+
+// isEncryptedKey reports whether the key holds a secret or a system entry encrypted with the CryptoService.
+func isEncryptedKey(key string) bool {
+	if _, _, err := helpers.ParseSecretPath(key); err == nil {
+		return true
+	}
+	for _, prefix := range encryptedStoragePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTokenRecordKey reports whether the key holds a token record rather than a secret or system data.
+func isTokenRecordKey(key string) bool {
+	return key != "" && !strings.Contains(key, ":")
+}
@@ -0,0 +1,261 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"go-secrets/helpers"
+	"sync"
+)
+
+const SealConfigStorageKey = "sys:seal"
+
+// SealType identifies how the master key is provided to unseal the server.
+type SealType string
+
+const (
+	// SealTypeKeyFile reads the master key from the master key file at startup.
+	SealTypeKeyFile SealType = "key_file"
+	// SealTypeShamir reconstructs the master key from a threshold of Shamir key shares submitted by operators.
+	SealTypeShamir SealType = "shamir"
+)
+
+var (
+	ErrInvalidUnsealKey      = errors.New("invalid unseal key")
+	ErrUnsealKeyAlreadyGiven = errors.New("unseal key share has already been provided")
+)
+
+// SealConfig describes how the master key is protected. It is stored in Redis at init; a missing
+// configuration means the master key is kept in the master key file.
+type SealConfig struct {
+	Type      SealType `json:"type"`
+	Shares    int      `json:"shares,omitempty"`
+	Threshold int      `json:"threshold,omitempty"`
+}
+
+// SealStatus reports whether the server is sealed and how many key shares have been submitted so far.
+// While unsealed it identifies the active root key by its version and fingerprint.
+type SealStatus struct {
+	Sealed         bool
+	Type           SealType
+	Shares         int
+	Threshold      int
+	Progress       int
+	KeyVersion     int
+	KeyFingerprint string
+}
+
+// SealService defines the methods for sealing and unsealing the server.
+// While sealed, the keyring is not loaded and no token or secret can be used.
+type SealService interface {
+	InitShares(ctx context.Context, shares int, threshold int) ([][]byte, error)
+	LoadConfig(ctx context.Context) error
+	Status() SealStatus
+	Sealed() bool
+	Unseal(ctx context.Context, key []byte) (SealStatus, error)
+	ResetUnseal() SealStatus
+	Seal()
+}
+
+type SealServiceImpl struct {
+	Redis   RedisService
+	Keyring KeyringService
+	Canary  CanaryService
+	mu      sync.Mutex
+	config  SealConfig
+	sealed  bool
+	shares  [][]byte
+}
+
+// NewSealService creates a sealed server using the key file seal type until LoadConfig reads the stored configuration.
+func NewSealService(redis RedisService, keyring KeyringService, canary CanaryService) SealService {
+	return &SealServiceImpl{
+		Redis:   redis,
+		Keyring: keyring,
+		Canary:  canary,
+		config:  SealConfig{Type: SealTypeKeyFile},
+		sealed:  true,
+	}
+}
+
+// InitShares creates the keyring with a new master key, splits the master key into the given number of
+// Shamir shares and stores the seal configuration. The master key itself is never persisted.
+func (s *SealServiceImpl) InitShares(ctx context.Context, shares int, threshold int) ([][]byte, error) {
+	masterKey := make([]byte, MasterKeySize)
+	if _, err := rand.Read(masterKey); err != nil {
+		return nil, err
+	}
+
+	keyShares, err := helpers.SplitSecret(masterKey, shares, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	config := SealConfig{Type: SealTypeShamir, Shares: shares, Threshold: threshold}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Keyring.Init(ctx, masterKey); err != nil {
+		return nil, err
+	}
+
+	// Without the seal config a restarted server would look for a master key file that was never written, so a
+	// keyring stored without it could never be unsealed and would block running init again
+	if err := s.Canary.Create(ctx); err != nil {
+		s.discardInit(ctx)
+		return nil, err
+	}
+	if err := s.Redis.Set(ctx, SealConfigStorageKey, string(data), 0); err != nil {
+		s.discardInit(ctx)
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.config = config
+	s.sealed = false
+	s.mu.Unlock()
+	return keyShares, nil
+}
+
+// discardInit unloads and deletes the keyring and canary written by an init that could not be completed, so init can
+// be run again.
+func (s *SealServiceImpl) discardInit(ctx context.Context) {
+	s.Keyring.Unload()
+	_ = s.Redis.Del(ctx, CanaryStorageKey)
+	_ = s.Redis.Del(ctx, KeyringStorageKey)
+}
+
+// LoadConfig reads the seal configuration stored at init.
+func (s *SealServiceImpl) LoadConfig(ctx context.Context) error {
+	config := SealConfig{Type: SealTypeKeyFile}
+
+	data, err := s.Redis.Get(ctx, SealConfigStorageKey)
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal([]byte(data), &config); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.config = config
+	s.mu.Unlock()
+	return nil
+}
+
+// Status returns the current seal status.
+func (s *SealServiceImpl) Status() SealStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status()
+}
+
+// Sealed reports whether the server is sealed.
+func (s *SealServiceImpl) Sealed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sealed
+}
+
+// Unseal submits a key towards unsealing the server. With the key file seal type the key is the master key itself;
+// with the Shamir seal type it is one key share, and the keyring is loaded once the threshold of shares is reached.
+// Submitted shares are discarded after every reconstruction attempt, successful or not.
+func (s *SealServiceImpl) Unseal(ctx context.Context, key []byte) (SealStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.sealed {
+		return s.status(), nil
+	}
+
+	masterKey := key
+	if s.config.Type == SealTypeShamir {
+		if len(key) != MasterKeySize+1 {
+			return s.status(), ErrInvalidUnsealKey
+		}
+		for _, share := range s.shares {
+			if share[MasterKeySize] == key[MasterKeySize] {
+				return s.status(), ErrUnsealKeyAlreadyGiven
+			}
+		}
+
+		s.shares = append(s.shares, append([]byte(nil), key...))
+		if len(s.shares) < s.config.Threshold {
+			return s.status(), nil
+		}
+
+		combined, err := helpers.CombineShares(s.shares)
+		s.resetShares()
+		if err != nil {
+			return s.status(), ErrInvalidUnsealKey
+		}
+		masterKey = combined
+	} else if len(key) != MasterKeySize {
+		return s.status(), ErrInvalidUnsealKey
+	}
+
+	if err := s.Keyring.Load(ctx, masterKey); err != nil {
+		clear(masterKey)
+		if errors.Is(err, ErrMasterKeyMismatch) {
+			return s.status(), ErrInvalidUnsealKey
+		}
+		return s.status(), err
+	}
+
+	// Refuse to unseal with key material that does not match the store
+	if err := s.Canary.Verify(ctx); err != nil {
+		s.Keyring.Unload()
+		return s.status(), err
+	}
+
+	s.sealed = false
+	return s.status(), nil
+}
+
+// ResetUnseal discards the key shares submitted so far.
+func (s *SealServiceImpl) ResetUnseal() SealStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resetShares()
+	return s.status()
+}
+
+// Seal unloads the keyring and wipes the master key from memory.
+func (s *SealServiceImpl) Seal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Keyring.Unload()
+	s.resetShares()
+	s.sealed = true
+}
+
+// resetShares overwrites and discards the submitted key shares. Callers must hold mu.
+func (s *SealServiceImpl) resetShares() {
+	for _, share := range s.shares {
+		clear(share)
+	}
+	s.shares = nil
+}
+
+// status builds the seal status. Callers must hold mu.
+func (s *SealServiceImpl) status() SealStatus {
+	status := SealStatus{Sealed: s.sealed, Type: s.config.Type, Progress: len(s.shares)}
+	if s.config.Type == SealTypeShamir {
+		status.Shares = s.config.Shares
+		status.Threshold = s.config.Threshold
+	}
+	if !s.sealed {
+		if rootKey, err := s.Keyring.ActiveKey(); err == nil {
+			status.KeyVersion = rootKey.Version
+			status.KeyFingerprint, _ = rootKey.Fingerprint()
+		}
+	}
+	return status
+}
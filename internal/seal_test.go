@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSealService returns a seal service over the given Redis, with its own keyring, crypto and canary services.
+func newTestSealService(t *testing.T, redis RedisService) SealService {
+	t.Helper()
+
+	keyring := NewKeyringService(redis)
+	t.Cleanup(keyring.Unload)
+	cipher, err := CipherByID(AlgorithmAES256GCM)
+	require.NoError(t, err)
+	canary := NewCanaryService(NewCryptoService(keyring, cipher), redis)
+	return NewSealService(redis, keyring, canary)
+}
+
+// failingSealConfig fails to store the seal configuration.
+type failingSealConfig struct {
+	*fakeRedis
+}
+
+func (f *failingSealConfig) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	if key == SealConfigStorageKey {
+		return errors.New("connection reset")
+	}
+	return f.fakeRedis.Set(ctx, key, value, ttl)
+}
+
+func TestSealShamir(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+	seal := newTestSealService(t, redis)
+
+	shares, err := seal.InitShares(ctx, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+	status := seal.Status()
+	assert.False(t, status.Sealed)
+	keyFingerprint := status.KeyFingerprint
+
+	// A restarted server only knows the stored configuration
+	restarted := newTestSealService(t, redis)
+	require.NoError(t, restarted.LoadConfig(ctx))
+
+	t.Run("unseals once the threshold of shares is submitted", func(t *testing.T) {
+		for i, share := range shares[1:4] {
+			status, err := restarted.Unseal(ctx, share)
+			require.NoError(t, err)
+			if i < 2 {
+				assert.True(t, status.Sealed)
+				assert.Equal(t, i+1, status.Progress)
+			} else {
+				assert.False(t, status.Sealed)
+				assert.Equal(t, 0, status.Progress)
+				assert.Equal(t, keyFingerprint, status.KeyFingerprint)
+			}
+		}
+	})
+
+	t.Run("rejects a share submitted twice", func(t *testing.T) {
+		restarted.Seal()
+
+		_, err := restarted.Unseal(ctx, shares[0])
+		require.NoError(t, err)
+		status, err := restarted.Unseal(ctx, shares[0])
+
+		assert.ErrorIs(t, err, ErrUnsealKeyAlreadyGiven)
+		assert.True(t, status.Sealed)
+		assert.Equal(t, 1, status.Progress)
+	})
+
+	t.Run("discards submitted shares on reset", func(t *testing.T) {
+		status := restarted.ResetUnseal()
+		assert.True(t, status.Sealed)
+		assert.Equal(t, 0, status.Progress)
+
+		// The share submitted before the reset can be submitted again
+		status, err := restarted.Unseal(ctx, shares[0])
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.Progress)
+		restarted.ResetUnseal()
+	})
+
+	t.Run("rejects shares of another master key", func(t *testing.T) {
+		otherShares, err := newTestSealService(t, newFakeRedis()).InitShares(ctx, 5, 3)
+		require.NoError(t, err)
+
+		for _, share := range otherShares[:2] {
+			_, err = restarted.Unseal(ctx, share)
+			require.NoError(t, err)
+		}
+		status, err := restarted.Unseal(ctx, otherShares[2])
+
+		assert.ErrorIs(t, err, ErrInvalidUnsealKey)
+		assert.True(t, status.Sealed)
+		assert.Equal(t, 0, status.Progress)
+	})
+
+	t.Run("rejects malformed shares", func(t *testing.T) {
+		_, err := restarted.Unseal(ctx, shares[0][:MasterKeySize])
+		assert.ErrorIs(t, err, ErrInvalidUnsealKey)
+	})
+}
+
+func TestSealCanaryMismatch(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+
+	masterKey := make([]byte, MasterKeySize)
+	_, err := rand.Read(masterKey)
+	require.NoError(t, err)
+	keyring := NewKeyringService(redis)
+	require.NoError(t, keyring.Init(ctx, append([]byte(nil), masterKey...)))
+	keyring.Unload()
+
+	// The canary was written with key material other than the stored keyring's
+	otherCrypto, _ := newTestCryptoService(t)
+	require.NoError(t, NewCanaryService(otherCrypto, redis).Create(ctx))
+
+	seal := newTestSealService(t, redis)
+	require.NoError(t, seal.LoadConfig(ctx))
+
+	status, err := seal.Unseal(ctx, masterKey)
+
+	assert.ErrorIs(t, err, ErrCanaryMismatch)
+	assert.True(t, status.Sealed)
+	assert.True(t, seal.Sealed())
+}
+
+func TestSealKeyFile(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+
+	masterKey := make([]byte, MasterKeySize)
+	_, err := rand.Read(masterKey)
+	require.NoError(t, err)
+	keyring := NewKeyringService(redis)
+	require.NoError(t, keyring.Init(ctx, append([]byte(nil), masterKey...)))
+	keyring.Unload()
+
+	seal := newTestSealService(t, redis)
+	require.NoError(t, seal.LoadConfig(ctx))
+
+	t.Run("rejects another master key", func(t *testing.T) {
+		otherKey := make([]byte, MasterKeySize)
+		_, err := rand.Read(otherKey)
+		require.NoError(t, err)
+
+		status, err := seal.Unseal(ctx, otherKey)
+
+		assert.ErrorIs(t, err, ErrInvalidUnsealKey)
+		assert.True(t, status.Sealed)
+	})
+
+	t.Run("unseals with the master key and creates a missing canary", func(t *testing.T) {
+		status, err := seal.Unseal(ctx, masterKey)
+
+		require.NoError(t, err)
+		assert.False(t, status.Sealed)
+		_, err = redis.Get(ctx, CanaryStorageKey)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSealInitSharesDiscardsKeyringWithoutConfig(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+
+	_, err := newTestSealService(t, &failingSealConfig{redis}).InitShares(ctx, 5, 3)
+	require.Error(t, err)
+	assert.Empty(t, redis.keys("sys:"))
+
+	// Init can be run again once Redis is back
+	seal := newTestSealService(t, redis)
+	shares, err := seal.InitShares(ctx, 5, 3)
+	require.NoError(t, err)
+	assert.Len(t, shares, 5)
+	assert.NoError(t, seal.LoadConfig(ctx))
+	assert.Equal(t, SealTypeShamir, seal.Status().Type)
+}
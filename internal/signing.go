@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"go-secrets/helpers"
+	"time"
+)
+
+// SigningStoragePrefix is the prefix of the storage keys holding signing keys, encrypted with the CryptoService.
+const SigningStoragePrefix = "sys:signing:"
+
+// SigningKeyType identifies the algorithm of a signing key.
+type SigningKeyType string
+
+const (
+	// SigningKeyTypeEd25519 signs the input itself with Ed25519.
+	SigningKeyTypeEd25519 SigningKeyType = "ed25519"
+	// SigningKeyTypeECDSAP256 signs the SHA-256 digest of the input with ECDSA on the P-256 curve,
+	// producing ASN.1 DER encoded signatures.
+	SigningKeyTypeECDSAP256 SigningKeyType = "ecdsa-p256"
+)
+
+var (
+	ErrSigningKeyNotFound        = errors.New("signing key does not exist")
+	ErrSigningKeyExists          = errors.New("signing key already exists")
+	ErrUnsupportedSigningKeyType = errors.New("unsupported signing key type, expected ed25519 or ecdsa-p256")
+)
+
+// SigningKey is a named asymmetric key whose private half never leaves the server. The private key is only decrypted
+// for a single operation and wiped afterwards, so the keys returned by SigningService only hold their public key.
+type SigningKey struct {
+	Name       string         `json:"name"`
+	Type       SigningKeyType `json:"type"`
+	PrivateKey []byte         `json:"private_key"` // PKCS #8 DER encoded
+	PublicKey  []byte         `json:"-"`           // PKIX DER encoded
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// SigningService defines the methods for managing signing keys and signing data with them.
+type SigningService interface {
+	CreateKey(ctx context.Context, name string, keyType SigningKeyType) (*SigningKey, error)
+	Key(ctx context.Context, name string) (*SigningKey, error)
+	Sign(ctx context.Context, name string, input []byte) ([]byte, error)
+	Verify(ctx context.Context, name string, input []byte, signature []byte) (bool, error)
+}
+
+type SigningServiceImpl struct {
+	Crypto CryptoService
+	Redis  RedisService
+}
+
+func NewSigningService(crypto CryptoService, redis RedisService) SigningService {
+	return &SigningServiceImpl{Crypto: crypto, Redis: redis}
+}
+
+// CreateKey generates a signing key of the given type.
+func (s *SigningServiceImpl) CreateKey(ctx context.Context, name string, keyType SigningKeyType) (*SigningKey, error) {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return nil, ErrInvalidKeyName
+	}
+
+	var privateKey crypto.Signer
+	var err error
+	switch keyType {
+	case SigningKeyTypeEd25519:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	case SigningKeyTypeECDSAP256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, ErrUnsupportedSigningKeyType
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer destroySigner(privateKey)
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		clear(der)
+		return nil, err
+	}
+
+	key := &SigningKey{Name: name, Type: keyType, PrivateKey: der, PublicKey: publicKey, CreatedAt: time.Now().UTC()}
+	defer key.destroy()
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(data)
+
+	encrypted, err := s.Crypto.Encrypt(string(data), SigningStoragePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.Redis.SetNX(ctx, SigningStoragePrefix+name, encrypted, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !stored {
+		return nil, ErrSigningKeyExists
+	}
+	return key, nil
+}
+
+// Key returns the signing key with the given name, with its public key and without its private key.
+func (s *SigningServiceImpl) Key(ctx context.Context, name string) (*SigningKey, error) {
+	key, signer, err := s.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	destroySigner(signer)
+	return key, nil
+}
+
+// Sign signs the input with the signing key.
+func (s *SigningServiceImpl) Sign(ctx context.Context, name string, input []byte) ([]byte, error) {
+	key, signer, err := s.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer destroySigner(signer)
+
+	if key.Type == SigningKeyTypeECDSAP256 {
+		digest := sha256.Sum256(input)
+		return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	return signer.Sign(rand.Reader, input, crypto.Hash(0))
+}
+
+// Verify reports whether the signature is a valid signature of the input by the signing key.
+func (s *SigningServiceImpl) Verify(ctx context.Context, name string, input []byte, signature []byte) (bool, error) {
+	_, signer, err := s.load(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	defer destroySigner(signer)
+
+	switch publicKey := signer.Public().(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(publicKey, input, signature), nil
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(input)
+		return ecdsa.VerifyASN1(publicKey, digest[:], signature), nil
+	default:
+		return false, ErrUnsupportedSigningKeyType
+	}
+}
+
+// load decrypts the signing key with the given name and parses its private key, which is wiped from the returned key.
+// Callers must destroy the returned signer once they are done with it.
+func (s *SigningServiceImpl) load(ctx context.Context, name string) (*SigningKey, crypto.Signer, error) {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return nil, nil, ErrSigningKeyNotFound
+	}
+
+	encrypted, err := s.Redis.Get(ctx, SigningStoragePrefix+name)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil, ErrSigningKeyNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	plaintext, err := s.Crypto.Decrypt(encrypted, SigningStoragePrefix+name)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := []byte(plaintext)
+	defer clear(data)
+
+	var key SigningKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, nil, err
+	}
+	defer key.destroy()
+
+	signer, err := key.signer()
+	if err != nil {
+		return nil, nil, err
+	}
+	key.PublicKey, err = x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		destroySigner(signer)
+		return nil, nil, err
+	}
+	return &key, signer, nil
+}
+
+// PublicKeyPEM returns the public half of the signing key as a PEM encoded PKIX public key.
+func (k *SigningKey) PublicKeyPEM() (string, error) {
+	if len(k.PublicKey) == 0 {
+		return "", fmt.Errorf("signing key %s has no public key", k.Name)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: k.PublicKey})), nil
+}
+
+// destroy overwrites and drops the private key.
+func (k *SigningKey) destroy() {
+	clear(k.PrivateKey)
+	k.PrivateKey = nil
+}
+
+// signer parses the private key of the signing key.
+func (k *SigningKey) signer() (crypto.Signer, error) {
+	privateKey, err := x509.ParsePKCS8PrivateKey(k.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s cannot sign", k.Name)
+	}
+	return signer, nil
+}
+
+// destroySigner overwrites a parsed private key.
+func destroySigner(signer crypto.Signer) {
+	switch privateKey := signer.(type) {
+	case ed25519.PrivateKey:
+		clear(privateKey)
+	case *ecdsa.PrivateKey:
+		clear(privateKey.D.Bits())
+	}
+}
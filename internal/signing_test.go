@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	crypto, keyring := newTestCryptoService(t)
+	signing := NewSigningService(crypto, newFakeRedis())
+	input := []byte("release-1.2.3.tar.gz")
+
+	for _, keyType := range []SigningKeyType{SigningKeyTypeEd25519, SigningKeyTypeECDSAP256} {
+		t.Run(string(keyType), func(t *testing.T) {
+			name := "release-" + string(keyType)
+			created, err := signing.CreateKey(ctx, name, keyType)
+			require.NoError(t, err)
+
+			signature, err := signing.Sign(ctx, name, input)
+			require.NoError(t, err)
+
+			valid, err := signing.Verify(ctx, name, input, signature)
+			require.NoError(t, err)
+			assert.True(t, valid)
+
+			t.Run("returns keys with their public key only", func(t *testing.T) {
+				key, err := signing.Key(ctx, name)
+				require.NoError(t, err)
+
+				for _, key := range []*SigningKey{created, key} {
+					assert.Nil(t, key.PrivateKey)
+					publicKey, err := key.PublicKeyPEM()
+					require.NoError(t, err)
+					assert.Contains(t, publicKey, "BEGIN PUBLIC KEY")
+				}
+				assert.Equal(t, created.PublicKey, key.PublicKey)
+			})
+
+			t.Run("rejects tampered input", func(t *testing.T) {
+				valid, err := signing.Verify(ctx, name, []byte("release-1.2.4.tar.gz"), signature)
+				require.NoError(t, err)
+				assert.False(t, valid)
+			})
+
+			t.Run("rejects tampered signatures", func(t *testing.T) {
+				tampered := append([]byte(nil), signature...)
+				tampered[len(tampered)-1] ^= 0x01
+
+				valid, err := signing.Verify(ctx, name, input, tampered)
+				require.NoError(t, err)
+				assert.False(t, valid)
+			})
+
+			t.Run("verifies after rotating the root key", func(t *testing.T) {
+				_, err := keyring.Rotate(ctx)
+				require.NoError(t, err)
+
+				valid, err := signing.Verify(ctx, name, input, signature)
+				require.NoError(t, err)
+				assert.True(t, valid)
+
+				rotated, err := signing.Sign(ctx, name, input)
+				require.NoError(t, err)
+				valid, err = signing.Verify(ctx, name, input, rotated)
+				require.NoError(t, err)
+				assert.True(t, valid)
+			})
+		})
+	}
+}
+
+func TestSigningRejectsOtherKeys(t *testing.T) {
+	ctx := context.Background()
+	crypto, _ := newTestCryptoService(t)
+	signing := NewSigningService(crypto, newFakeRedis())
+	input := []byte("release-1.2.3.tar.gz")
+
+	for _, key := range []struct {
+		name    string
+		keyType SigningKeyType
+	}{
+		{"release", SigningKeyTypeEd25519},
+		{"staging", SigningKeyTypeEd25519},
+		{"ecdsa", SigningKeyTypeECDSAP256},
+	} {
+		_, err := signing.CreateKey(ctx, key.name, key.keyType)
+		require.NoError(t, err)
+	}
+
+	signature, err := signing.Sign(ctx, "release", input)
+	require.NoError(t, err)
+
+	for _, name := range []string{"staging", "ecdsa"} {
+		valid, err := signing.Verify(ctx, name, input, signature)
+		require.NoError(t, err)
+		assert.False(t, valid, name)
+	}
+
+	_, err = signing.Sign(ctx, "missing", input)
+	assert.ErrorIs(t, err, ErrSigningKeyNotFound)
+	_, err = signing.CreateKey(ctx, "release", SigningKeyTypeEd25519)
+	assert.ErrorIs(t, err, ErrSigningKeyExists)
+	_, err = signing.CreateKey(ctx, "rsa", SigningKeyType("rsa"))
+	assert.ErrorIs(t, err, ErrUnsupportedSigningKeyType)
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"context"
+	"strings"
+)
+
+// StoreStats counts the entries in the store by kind.
+type StoreStats struct {
+	Keys             int
+	Tokens           int
+	Secrets          int
+	BlobChunks       int
+	Policies         int
+	PasswordPolicies int
+	TransitKeys      int
+	SigningKeys      int
+	AppRoles         int
+}
+
+// StatsService defines the methods for collecting statistics about the store.
+type StatsService interface {
+	Collect(ctx context.Context) (*StoreStats, error)
+}
+
+type StatsServiceImpl struct {
+	Redis RedisService
+}
+
+func NewStatsService(redis RedisService) StatsService {
+	return &StatsServiceImpl{Redis: redis}
+}
+
+// Collect scans every key in the store and counts it by kind. Keys of other kinds, such as token counters and child
+// links, only count towards the total.
+func (s *StatsServiceImpl) Collect(ctx context.Context) (*StoreStats, error) {
+	iter, err := s.Redis.NewScanner(ctx, "*")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &StoreStats{}
+	for iter.Next(ctx) {
+		key := iter.Val()
+		stats.Keys++
+
+		switch {
+		case isTokenRecordKey(key):
+			stats.Tokens++
+		case strings.HasPrefix(key, PolicyStoragePrefix):
+			stats.Policies++
+		case strings.HasPrefix(key, PasswordPolicyStoragePrefix):
+			stats.PasswordPolicies++
+		case strings.HasPrefix(key, TransitStoragePrefix):
+			stats.TransitKeys++
+		case strings.HasPrefix(key, SigningStoragePrefix):
+			stats.SigningKeys++
+		case strings.HasPrefix(key, AppRoleStoragePrefix+"role:"):
+			stats.AppRoles++
+		default:
+			_, rest, _ := strings.Cut(key, ":")
+			if strings.HasPrefix(rest, "secret:") {
+				stats.Secrets++
+			} else if strings.HasPrefix(rest, "blob:") {
+				stats.BlobChunks++
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrInvalidTLSConfig = errors.New("invalid tls configuration")
+
+// LoadTLSConfig builds the TLS configuration the API is served with from a PEM certificate chain and key. With a
+// PEM bundle of client CAs, client certificates are verified against it and can authenticate requests; they are
+// required when requireClientCert is set and optional otherwise.
+func LoadTLSConfig(certFile string, keyFile string, clientCAFile string, requireClientCert bool) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("%w: both a certificate and a key file are needed", ErrInvalidTLSConfig)
+	}
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.NoClientCert,
+	}
+	if clientCAFile == "" {
+		if requireClientCert {
+			return nil, fmt.Errorf("%w: requiring client certificates needs a client CA file", ErrInvalidTLSConfig)
+		}
+		return config, nil
+	}
+
+	bundle, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	config.ClientCAs = x509.NewCertPool()
+	if !config.ClientCAs.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("%w: no certificates found in %s", ErrInvalidTLSConfig, clientCAFile)
+	}
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	if requireClientCert {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
@@ -4,26 +4,117 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
-	"go-secrets/config"
-	"go-secrets/helpers"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
+// TokenRecord is the value stored in Redis under a token's HMAC.
+// The namespace under which the token's secrets are stored is fixed when the token is issued,
+// so re-hashing the token with a newer key version does not move its secrets.
+// The accessor identifies the token without being a secret, so it can be logged and used to manage the token.
+// A root token never expires and may only be used for administration.
+// A token without policies has full rights over its own namespace; one with policies may only do what they allow.
+// A child token records the namespace of the token that created it, which stays the same when either is re-hashed.
+// Renewing a token never extends it past its max TTL, counted from its creation. The number of requests a token
+// made and, for a token with a use limit, its remaining uses are kept in separate counters, so they can be changed
+// atomically.
+type TokenRecord struct {
+	KeyVersion  int               `json:"key_version"`
+	Namespace   string            `json:"namespace"`
+	Accessor    string            `json:"accessor,omitempty"`
+	Root        bool              `json:"root,omitempty"`
+	DisplayName string            `json:"display_name,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Policies    []string          `json:"policies,omitempty"`
+	Parent      string            `json:"parent,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	CreatorIP   string            `json:"creator_ip,omitempty"`
+	TTL         int               `json:"ttl,omitempty"`      // TTL the token was issued with, in seconds
+	MaxTTL      int               `json:"max_ttl,omitempty"`  // Seconds
+	NumUses     int               `json:"num_uses,omitempty"` // Use limit the token was issued with, 0 for unlimited
+	HMAC        string            `json:"-"`
+}
+
+// MaxExpiry returns the time past which the token cannot be renewed. Records written before renewal was supported
+// have no max TTL and return the zero time.
+func (r *TokenRecord) MaxExpiry() time.Time {
+	if r.MaxTTL == 0 || r.CreatedAt.IsZero() {
+		return time.Time{}
+	}
+	return r.CreatedAt.Add(time.Duration(r.MaxTTL) * time.Second)
+}
+
+// AccessorStoragePrefix is the prefix of the storage keys mapping token accessors to the HMAC of their token record.
+const AccessorStoragePrefix = "sys:accessor:"
+
+// accessorByteLength is the number of random bytes in a token accessor.
+const accessorByteLength = 16
+
+var (
+	ErrTokenNotFound  = errors.New("token does not exist")
+	ErrTokenExhausted = errors.New("token has no uses left")
+)
+
+// childLinkKey returns the key under which a parent token tracks a child, holding the child's current HMAC.
+// It lives in the parent's namespace, so it is deleted along with the parent's other entries.
+func childLinkKey(parentNamespace string, childNamespace string) string {
+	return fmt.Sprintf("%s:child:%s", parentNamespace, childNamespace)
+}
+
+// tokenAccessorContextKey is the request context key holding the accessor of the request's token.
+type tokenAccessorContextKey struct{}
+
+// WithTokenAccessor returns a copy of the context that carries the accessor of the request's token, for logging.
+func WithTokenAccessor(ctx context.Context, accessor string) context.Context {
+	return context.WithValue(ctx, tokenAccessorContextKey{}, accessor)
+}
+
+// TokenAccessor returns the accessor of the request's token carried by the context, if any.
+func TokenAccessor(ctx context.Context) string {
+	accessor, _ := ctx.Value(tokenAccessorContextKey{}).(string)
+	return accessor
+}
+
+// usesKey returns the key of the counter holding a use-limited token's remaining uses. It lives in the token's
+// namespace, so it is renewed and deleted along with the token's other entries.
+func usesKey(namespace string) string {
+	return namespace + ":uses"
+}
+
+// requestsKey returns the key of the counter holding the number of requests a token authenticated.
+func requestsKey(namespace string) string {
+	return namespace + ":requests"
+}
+
 type TokenService interface {
 	GenerateToken(byteLength ...int) (string, error)
 	GetHeaderToken(ctx *gin.Context) (string, error)
 	AuthTokenHMAC(ctx *gin.Context) (string, error)
-	ValidateToken(token string, r RedisService) (bool, error)
+	AuthTokenNamespace(ctx *gin.Context) (string, error)
+	AuthTokenRecord(ctx *gin.Context, r RedisService) (*TokenRecord, error)
+	StoreToken(ctx context.Context, token string, ttl time.Duration, record TokenRecord, r RedisService) (*TokenRecord, error)
+	LookupToken(ctx context.Context, token string, r RedisService) (*TokenRecord, error)
+	LookupAccessor(ctx context.Context, accessor string, r RedisService) (*TokenRecord, error)
+	ListAccessors(ctx context.Context, r RedisService) ([]string, error)
+	UseToken(ctx context.Context, record *TokenRecord, r RedisService) (int, error)
+	RequestCount(ctx context.Context, record *TokenRecord, r RedisService) (int, error)
+	RemainingUses(ctx context.Context, record *TokenRecord, r RedisService) (int, error)
+	RenewToken(ctx context.Context, record *TokenRecord, increment time.Duration, r RedisService) (time.Duration, error)
+	RevokeToken(ctx context.Context, record *TokenRecord, r RedisService) error
 }
 
-type TokenServiceImpl struct{}
+type TokenServiceImpl struct {
+	Keyring KeyringService
+}
 
-func NewTokenService() TokenService {
-	return &TokenServiceImpl{}
+func NewTokenService(keyring KeyringService) TokenService {
+	return &TokenServiceImpl{Keyring: keyring}
 }
 
 // GenerateToken generates a random token of the specified byte length (defaulting to DefaultByteLength if not provided).
@@ -41,16 +132,393 @@ func (t *TokenServiceImpl) GenerateToken(byteLength ...int) (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// ValidateToken checks if the given HMAC token is valid by verifying its presence and value in Redis.
-func (t *TokenServiceImpl) ValidateToken(token string, r RedisService) (bool, error) {
-	val, err := r.Get(context.Background(), token)
-	if err == redis.Nil || val == "" {
-		return false, nil // Token does not exist, but it's not an error
+// StoreToken stores a new token record under the token's HMAC computed with the active root key. The record's key
+// version, HMAC and accessor are filled in, and its namespace defaults to the HMAC; when it names a parent, the parent
+// tracks it so revoking the parent also revokes it.
+func (t *TokenServiceImpl) StoreToken(ctx context.Context, token string, ttl time.Duration, record TokenRecord, r RedisService) (*TokenRecord, error) {
+	rootKey, err := t.Keyring.ActiveKey()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenHMAC, err := hmacWithKey(token, rootKey)
+	if err != nil {
+		return nil, err
+	}
+
+	accessor, err := t.GenerateToken(accessorByteLength)
+	if err != nil {
+		return nil, err
+	}
+
+	record.KeyVersion = rootKey.Version
+	if record.Namespace == "" {
+		record.Namespace = tokenHMAC
+	}
+	record.Accessor = accessor
+	record.HMAC = tokenHMAC
+
+	entries := map[string]string{
+		requestsKey(record.Namespace):    "0",
+		AccessorStoragePrefix + accessor: record.HMAC,
+	}
+	if record.NumUses > 0 {
+		entries[usesKey(record.Namespace)] = strconv.Itoa(record.NumUses)
+	}
+	if record.Parent != "" {
+		entries[childLinkKey(record.Parent, record.Namespace)] = record.HMAC
+	}
+
+	// Write the entries pointing at the record first, so the record never exists without them
+	for key, value := range entries {
+		if err := r.Set(ctx, key, value, ttl); err != nil {
+			t.discardEntries(ctx, entries, r)
+			return nil, err
+		}
+	}
+	if err := t.writeRecord(ctx, &record, ttl, r); err != nil {
+		t.discardEntries(ctx, entries, r)
+		return nil, err
+	}
+	return &record, nil
+}
+
+// discardEntries deletes the entries written for a token record that could not be stored.
+func (t *TokenServiceImpl) discardEntries(ctx context.Context, entries map[string]string, r RedisService) {
+	for key := range entries {
+		_ = r.Del(ctx, key)
+	}
+}
+
+// LookupAccessor finds the record of the token with the given accessor.
+func (t *TokenServiceImpl) LookupAccessor(ctx context.Context, accessor string, r RedisService) (*TokenRecord, error) {
+	tokenHMAC, err := r.Get(ctx, AccessorStoragePrefix+accessor)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, ErrTokenNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	value, err := r.Get(ctx, tokenHMAC)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, ErrTokenNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	record, err := ParseTokenRecord(tokenHMAC, value)
+	if err != nil {
+		return nil, err
+	}
+	if record.Accessor != accessor {
+		return nil, ErrTokenNotFound
+	}
+	return record, nil
+}
+
+// ListAccessors returns the accessors of all tokens that have one, in no particular order.
+func (t *TokenServiceImpl) ListAccessors(ctx context.Context, r RedisService) ([]string, error) {
+	iter, err := r.NewScanner(ctx, AccessorStoragePrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+
+	accessors := []string{}
+	for iter.Next(ctx) {
+		accessors = append(accessors, strings.TrimPrefix(iter.Val(), AccessorStoragePrefix))
+	}
+	return accessors, iter.Err()
+}
+
+// LookupToken finds the token record by computing the token's HMAC with each root key version, active first.
+// A token found under an older key version is re-hashed with the active key so that version can later be retired.
+func (t *TokenServiceImpl) LookupToken(ctx context.Context, token string, r RedisService) (*TokenRecord, error) {
+	rootKeys, err := t.Keyring.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rootKey := range rootKeys {
+		tokenHMAC, err := hmacWithKey(token, rootKey)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := r.Get(ctx, tokenHMAC)
+		if errors.Is(err, ErrKeyNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		record, err := ParseTokenRecord(tokenHMAC, val)
+		if err != nil {
+			return nil, err
+		}
+		if rootKey != rootKeys[0] {
+			return t.rehashToken(ctx, token, record, rootKeys[0], r)
+		}
+		return record, nil
+	}
+
+	return nil, ErrTokenNotFound
+}
+
+// ParseTokenRecord decodes a token record stored under the given HMAC.
+// Records written before key versioning hold the string "1" and use their HMAC as namespace.
+func ParseTokenRecord(tokenHMAC string, value string) (*TokenRecord, error) {
+	record := &TokenRecord{KeyVersion: 1, Namespace: tokenHMAC}
+	if value != "1" {
+		if err := json.Unmarshal([]byte(value), record); err != nil {
+			return nil, fmt.Errorf("invalid token record: %w", err)
+		}
+	}
+	record.HMAC = tokenHMAC
+	return record, nil
+}
+
+// rehashToken moves a token record to the HMAC computed with the given root key, keeping its TTL and the rest of the record.
+func (t *TokenServiceImpl) rehashToken(ctx context.Context, token string, record *TokenRecord, rootKey *KeyringKey, r RedisService) (*TokenRecord, error) {
+	ttl, err := r.TTL(ctx, record.HMAC)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == NoExpiry {
+		ttl = 0
+	} else if ttl <= 0 {
+		return nil, ErrTokenNotFound
+	}
+
+	tokenHMAC, err := hmacWithKey(token, rootKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rehashed := *record
+	rehashed.KeyVersion = rootKey.Version
+	rehashed.HMAC = tokenHMAC
+	if err := t.writeRecord(ctx, &rehashed, ttl, r); err != nil {
+		return nil, err
+	}
+	if err := r.Del(ctx, record.HMAC); err != nil {
+		return nil, err
+	}
+
+	// Keep the parent's link and the accessor pointing at the record, so it can still be found by them
+	if rehashed.Parent != "" {
+		if err := r.Set(ctx, childLinkKey(rehashed.Parent, rehashed.Namespace), rehashed.HMAC, ttl); err != nil {
+			return nil, err
+		}
+	}
+	if rehashed.Accessor != "" {
+		if err := r.Set(ctx, AccessorStoragePrefix+rehashed.Accessor, rehashed.HMAC, ttl); err != nil {
+			return nil, err
+		}
+	}
+	return &rehashed, nil
+}
+
+// UseToken counts a request made with the token and, for a use-limited token, consumes one use and returns the uses
+// left after it. It returns ErrTokenExhausted when no uses were left. Requests of tokens issued before they were
+// counted are not.
+func (t *TokenServiceImpl) UseToken(ctx context.Context, record *TokenRecord, r RedisService) (int, error) {
+	if _, err := r.IncrementBy(ctx, requestsKey(record.Namespace), 1); err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return 0, err
+	}
+	if record.NumUses == 0 {
+		return 0, nil
+	}
+
+	remaining, err := r.IncrementBy(ctx, usesKey(record.Namespace), -1)
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, ErrTokenExhausted
 	} else if err != nil {
-		return false, err
+		return 0, err
+	}
+	if remaining < 0 {
+		return 0, ErrTokenExhausted
 	}
+	return int(remaining), nil
+}
 
-	return true, nil
+// RequestCount returns the number of requests the token authenticated.
+func (t *TokenServiceImpl) RequestCount(ctx context.Context, record *TokenRecord, r RedisService) (int, error) {
+	value, err := r.Get(ctx, requestsKey(record.Namespace))
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+// RemainingUses returns the uses left of a use-limited token without consuming one.
+func (t *TokenServiceImpl) RemainingUses(ctx context.Context, record *TokenRecord, r RedisService) (int, error) {
+	value, err := r.Get(ctx, usesKey(record.Namespace))
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	remaining, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	return max(remaining, 0), nil
+}
+
+// RenewToken extends the token's TTL to the increment from now, bounded by its max TTL, along with the TTLs of the
+// secrets and other entries in its namespace, which were stored with the token's TTL. It never shortens the TTL and
+// returns the resulting one. Child tokens keep their own TTLs.
+func (t *TokenServiceImpl) RenewToken(ctx context.Context, record *TokenRecord, increment time.Duration, r RedisService) (time.Duration, error) {
+	current, err := r.TTL(ctx, record.HMAC)
+	if err != nil {
+		return 0, err
+	}
+	if current <= 0 {
+		return 0, ErrTokenNotFound
+	}
+
+	maxExpiry := record.MaxExpiry()
+	if maxExpiry.IsZero() {
+		return current, nil
+	}
+	ttl := min(increment, time.Until(maxExpiry)).Truncate(time.Second)
+	if ttl <= current {
+		return current, nil
+	}
+
+	if err := r.Expire(ctx, record.HMAC, ttl); err != nil {
+		return 0, err
+	}
+	if record.Parent != "" {
+		if err := r.Expire(ctx, childLinkKey(record.Parent, record.Namespace), ttl); err != nil {
+			return 0, err
+		}
+	}
+	if record.Accessor != "" {
+		if err := r.Expire(ctx, AccessorStoragePrefix+record.Accessor, ttl); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := expireNamespace(ctx, record, ttl, r); err != nil {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+// expireNamespace sets the TTL of the secrets and other entries in the token's namespace, except its child links.
+func expireNamespace(ctx context.Context, record *TokenRecord, ttl time.Duration, r RedisService) error {
+	iter, err := r.NewScanner(ctx, record.Namespace+"*")
+	if err != nil {
+		return err
+	}
+
+	pipeline, err := r.NewPipeline(ctx)
+	if err != nil {
+		return err
+	}
+	childPrefix := childLinkKey(record.Namespace, "")
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if key == record.HMAC || strings.HasPrefix(key, childPrefix) {
+			continue
+		}
+		if err := pipeline.Expire(ctx, key, ttl); err != nil {
+			pipeline.Discard()
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		pipeline.Discard()
+		return err
+	}
+
+	_, err = pipeline.Exec(ctx)
+	return err
+}
+
+// RevokeToken deletes the token record and everything stored in its namespace, after revoking the child tokens it
+// tracks the same way. Orphan children are not tracked and outlive it.
+func (t *TokenServiceImpl) RevokeToken(ctx context.Context, record *TokenRecord, r RedisService) error {
+	iter, err := r.NewScanner(ctx, record.Namespace+"*")
+	if err != nil {
+		return err
+	}
+
+	// The token record is stored under a different key than its namespace once it has been re-hashed
+	keysToDelete := []string{record.HMAC}
+	if record.Accessor != "" {
+		keysToDelete = append(keysToDelete, AccessorStoragePrefix+record.Accessor)
+	}
+	childPrefix := childLinkKey(record.Namespace, "")
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if key == record.HMAC {
+			continue
+		}
+		keysToDelete = append(keysToDelete, key)
+
+		if childNamespace, found := strings.CutPrefix(key, childPrefix); found {
+			if err := t.revokeChild(ctx, key, childNamespace, r); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	pipeline, err := r.NewPipeline(ctx)
+	if err != nil {
+		return err
+	}
+	for _, key := range keysToDelete {
+		if err := pipeline.Del(ctx, key); err != nil {
+			pipeline.Discard()
+			return err
+		}
+	}
+	_, err = pipeline.Exec(ctx)
+	return err
+}
+
+// revokeChild revokes the child token a link points at. Links outlive the child's record when it expired or was
+// revoked on its own, so a missing record or one from another namespace is skipped.
+func (t *TokenServiceImpl) revokeChild(ctx context.Context, linkKey string, childNamespace string, r RedisService) error {
+	childHMAC, err := r.Get(ctx, linkKey)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	value, err := r.Get(ctx, childHMAC)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	child, err := ParseTokenRecord(childHMAC, value)
+	if err != nil {
+		return err
+	}
+	if child.Namespace != childNamespace {
+		return nil
+	}
+	return t.RevokeToken(ctx, child, r)
+}
+
+// writeRecord stores the token record under its HMAC with the given TTL.
+func (t *TokenServiceImpl) writeRecord(ctx context.Context, record *TokenRecord, ttl time.Duration, r RedisService) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return r.Set(ctx, record.HMAC, string(value), ttl)
 }
 
 // GetHeaderToken retrieves the token from the Authorization header in the request.
@@ -65,22 +533,46 @@ func (t *TokenServiceImpl) GetHeaderToken(ctx *gin.Context) (string, error) {
 	return parts[1], nil
 }
 
-// AuthTokenHMAC computes the HMAC of the token from the request header.
+// AuthTokenHMAC returns the HMAC under which the request's token is stored.
+// It uses the HMAC resolved by the auth middleware and falls back to computing it with the active root key.
 func (t *TokenServiceImpl) AuthTokenHMAC(ctx *gin.Context) (string, error) {
+	if tokenHMAC := ctx.GetString("token_hmac"); tokenHMAC != "" {
+		return tokenHMAC, nil
+	}
+
 	token, err := t.GetHeaderToken(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	serverToken, err := config.GetServerToken()
+	rootKey, err := t.Keyring.ActiveKey()
 	if err != nil {
 		return "", err
 	}
 
-	tokenHMAC, err := helpers.GenerateHMAC(token, serverToken)
+	return hmacWithKey(token, rootKey)
+}
+
+// AuthTokenRecord returns the record of the request's token.
+func (t *TokenServiceImpl) AuthTokenRecord(ctx *gin.Context, r RedisService) (*TokenRecord, error) {
+	tokenHMAC, err := t.AuthTokenHMAC(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return tokenHMAC, nil
+	value, err := r.Get(ctx.Request.Context(), tokenHMAC)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, ErrTokenNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return ParseTokenRecord(tokenHMAC, value)
+}
+
+// AuthTokenNamespace returns the namespace under which the request's token stores its secrets.
+func (t *TokenServiceImpl) AuthTokenNamespace(ctx *gin.Context) (string, error) {
+	if namespace := ctx.GetString("token_namespace"); namespace != "" {
+		return namespace, nil
+	}
+	return t.AuthTokenHMAC(ctx)
 }
@@ -0,0 +1,213 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeTestToken stores a new token with the given record and TTL, and a secret in its namespace.
+func storeTestToken(t *testing.T, tokens TokenService, redis *fakeRedis, record TokenRecord, ttl time.Duration) (*TokenRecord, string) {
+	t.Helper()
+
+	token, err := tokens.GenerateToken()
+	require.NoError(t, err)
+	record.CreatedAt = time.Now()
+	stored, err := tokens.StoreToken(context.Background(), token, ttl, record, redis)
+	require.NoError(t, err)
+	require.NoError(t, redis.Set(context.Background(), testSecretPath(t, stored.Namespace, "db"), "value", ttl))
+	return stored, token
+}
+
+func TestRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+	tokens := newTestTokenService(t, redis)
+
+	parent, _ := storeTestToken(t, tokens, redis, TokenRecord{}, time.Hour)
+	child, _ := storeTestToken(t, tokens, redis, TokenRecord{Parent: parent.Namespace}, time.Hour)
+	grandchild, _ := storeTestToken(t, tokens, redis, TokenRecord{Parent: child.Namespace}, time.Hour)
+	orphan, orphanToken := storeTestToken(t, tokens, redis, TokenRecord{}, time.Hour)
+	sibling, _ := storeTestToken(t, tokens, redis, TokenRecord{}, time.Hour)
+	siblingChild, _ := storeTestToken(t, tokens, redis, TokenRecord{Parent: sibling.Namespace}, time.Hour)
+
+	require.NoError(t, tokens.RevokeToken(ctx, parent, redis))
+
+	t.Run("revokes the whole subtree with its secrets", func(t *testing.T) {
+		for _, record := range []*TokenRecord{parent, child, grandchild} {
+			_, err := tokens.LookupAccessor(ctx, record.Accessor, redis)
+			assert.ErrorIs(t, err, ErrTokenNotFound)
+			assert.Empty(t, redis.keys(record.Namespace))
+			assert.Empty(t, redis.keys(AccessorStoragePrefix+record.Accessor))
+		}
+	})
+
+	t.Run("keeps orphans and other trees", func(t *testing.T) {
+		record, err := tokens.LookupToken(ctx, orphanToken, redis)
+		require.NoError(t, err)
+		assert.Equal(t, orphan.Namespace, record.Namespace)
+
+		for _, record := range []*TokenRecord{orphan, sibling, siblingChild} {
+			_, err := tokens.LookupAccessor(ctx, record.Accessor, redis)
+			assert.NoError(t, err)
+			_, err = redis.Get(ctx, testSecretPath(t, record.Namespace, "db"))
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("skips children revoked on their own", func(t *testing.T) {
+		require.NoError(t, tokens.RevokeToken(ctx, siblingChild, redis))
+
+		assert.NoError(t, tokens.RevokeToken(ctx, sibling, redis))
+		assert.Empty(t, redis.keys(sibling.Namespace))
+	})
+}
+
+func TestRenewToken(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+	tokens := newTestTokenService(t, redis)
+
+	t.Run("extends the token, its links and its secrets", func(t *testing.T) {
+		parent, _ := storeTestToken(t, tokens, redis, TokenRecord{MaxTTL: 3600}, time.Hour)
+		record, _ := storeTestToken(t, tokens, redis, TokenRecord{Parent: parent.Namespace, MaxTTL: 3600}, time.Minute)
+		child, _ := storeTestToken(t, tokens, redis, TokenRecord{Parent: record.Namespace}, time.Minute)
+
+		ttl, err := tokens.RenewToken(ctx, record, 10*time.Minute, redis)
+
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Minute, ttl)
+		for _, key := range []string{
+			record.HMAC,
+			AccessorStoragePrefix + record.Accessor,
+			childLinkKey(parent.Namespace, record.Namespace),
+			testSecretPath(t, record.Namespace, "db"),
+			requestsKey(record.Namespace),
+		} {
+			keyTTL, _ := redis.TTL(ctx, key)
+			assert.Equal(t, 10*time.Minute, keyTTL, logKey(key))
+		}
+
+		// Children keep their own TTL
+		childTTL, _ := redis.TTL(ctx, child.HMAC)
+		assert.Equal(t, time.Minute, childTTL)
+	})
+
+	t.Run("caps the TTL at the max TTL", func(t *testing.T) {
+		record, _ := storeTestToken(t, tokens, redis, TokenRecord{MaxTTL: 120}, time.Minute)
+
+		ttl, err := tokens.RenewToken(ctx, record, time.Hour, redis)
+
+		require.NoError(t, err)
+		assert.LessOrEqual(t, ttl, 120*time.Second)
+		assert.Greater(t, ttl, 110*time.Second)
+		keyTTL, _ := redis.TTL(ctx, testSecretPath(t, record.Namespace, "db"))
+		assert.Equal(t, ttl, keyTTL)
+	})
+
+	t.Run("does not extend past the max TTL or shorten the TTL", func(t *testing.T) {
+		expired, _ := storeTestToken(t, tokens, redis, TokenRecord{MaxTTL: 120}, time.Minute)
+		expired.CreatedAt = time.Now().Add(-2 * time.Minute)
+		unbounded, _ := storeTestToken(t, tokens, redis, TokenRecord{}, time.Minute)
+		bounded, _ := storeTestToken(t, tokens, redis, TokenRecord{MaxTTL: 3600}, time.Minute)
+
+		for _, tt := range []struct {
+			record    *TokenRecord
+			increment time.Duration
+		}{
+			{expired, time.Hour},
+			{unbounded, time.Hour},
+			{bounded, 10 * time.Second},
+		} {
+			ttl, err := tokens.RenewToken(ctx, tt.record, tt.increment, redis)
+
+			require.NoError(t, err)
+			assert.Equal(t, time.Minute, ttl)
+			keyTTL, _ := redis.TTL(ctx, tt.record.HMAC)
+			assert.Equal(t, time.Minute, keyTTL)
+		}
+	})
+
+	t.Run("rejects expired tokens", func(t *testing.T) {
+		record, _ := storeTestToken(t, tokens, redis, TokenRecord{MaxTTL: 3600}, time.Minute)
+		redis.advance(time.Minute)
+
+		_, err := tokens.RenewToken(ctx, record, time.Hour, redis)
+		assert.ErrorIs(t, err, ErrTokenNotFound)
+	})
+}
+
+func TestUseToken(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+	tokens := newTestTokenService(t, redis)
+
+	t.Run("counts down to exhaustion", func(t *testing.T) {
+		record, _ := storeTestToken(t, tokens, redis, TokenRecord{NumUses: 2}, time.Hour)
+
+		for _, expected := range []int{1, 0} {
+			remaining, err := tokens.UseToken(ctx, record, redis)
+			require.NoError(t, err)
+			assert.Equal(t, expected, remaining)
+		}
+		_, err := tokens.UseToken(ctx, record, redis)
+		assert.ErrorIs(t, err, ErrTokenExhausted)
+
+		remaining, err := tokens.RemainingUses(ctx, record, redis)
+		require.NoError(t, err)
+		assert.Equal(t, 0, remaining)
+		count, err := tokens.RequestCount(ctx, record, redis)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("allows exactly the use limit under concurrent requests", func(t *testing.T) {
+		record, _ := storeTestToken(t, tokens, redis, TokenRecord{NumUses: 5}, time.Hour)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		used, exhausted := 0, 0
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := tokens.UseToken(ctx, record, redis)
+				mu.Lock()
+				defer mu.Unlock()
+				if errors.Is(err, ErrTokenExhausted) {
+					exhausted++
+				} else if assert.NoError(t, err) {
+					used++
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 5, used)
+		assert.Equal(t, 15, exhausted)
+	})
+
+	t.Run("does not recreate an expired counter", func(t *testing.T) {
+		record, _ := storeTestToken(t, tokens, redis, TokenRecord{NumUses: 2}, time.Minute)
+		redis.advance(time.Minute)
+
+		_, err := tokens.UseToken(ctx, record, redis)
+		assert.ErrorIs(t, err, ErrTokenExhausted)
+		assert.Empty(t, redis.keys(record.Namespace))
+	})
+
+	t.Run("never exhausts tokens without a use limit", func(t *testing.T) {
+		record, _ := storeTestToken(t, tokens, redis, TokenRecord{}, time.Hour)
+
+		for range 3 {
+			remaining, err := tokens.UseToken(ctx, record, redis)
+			require.NoError(t, err)
+			assert.Equal(t, 0, remaining)
+		}
+	})
+}
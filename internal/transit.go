@@ -0,0 +1,328 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-secrets/helpers"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TransitStoragePrefix is the prefix of the storage keys holding transit keys, encrypted with the CryptoService.
+const TransitStoragePrefix = "sys:transit:"
+
+// TransitCiphertextPrefix marks ciphertexts produced by the transit engine, laid out as
+// `transit:v<key version>:<base64(nonce || ciphertext)>`.
+const TransitCiphertextPrefix = "transit"
+
+// TransitKeySize is the size of transit key versions and generated data keys.
+const TransitKeySize = 32
+
+var (
+	ErrInvalidKeyName              = errors.New("key names may only contain letters, digits, underscores and dashes")
+	ErrTransitKeyNotFound          = errors.New("transit key does not exist")
+	ErrTransitKeyExists            = errors.New("transit key already exists")
+	ErrInvalidTransitCiphertext    = errors.New("invalid transit ciphertext")
+	ErrTransitKeyVersionDisabled   = errors.New("ciphertext key version is below the minimum decryption version")
+	ErrInvalidMinDecryptionVersion = errors.New("minimum decryption version must be between 1 and the latest key version")
+)
+
+// TransitKeyVersion is a single version of a transit key.
+type TransitKeyVersion struct {
+	Key       []byte    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransitKey is a named, versioned encryption key that never leaves the server. New data is encrypted with the
+// latest version, and data encrypted with versions below MinDecryptionVersion can no longer be decrypted.
+// Its key material is only decrypted for a single operation and wiped afterwards, so the keys returned by
+// TransitService do not hold it.
+type TransitKey struct {
+	Name                 string                     `json:"name"`
+	LatestVersion        int                        `json:"latest_version"`
+	MinDecryptionVersion int                        `json:"min_decryption_version"`
+	Versions             map[int]*TransitKeyVersion `json:"versions"`
+	CreatedAt            time.Time                  `json:"created_at"`
+}
+
+// TransitService defines the methods for managing transit keys and encrypting data with them without storing it.
+type TransitService interface {
+	CreateKey(ctx context.Context, name string) (*TransitKey, error)
+	Key(ctx context.Context, name string) (*TransitKey, error)
+	RotateKey(ctx context.Context, name string) (*TransitKey, error)
+	SetMinDecryptionVersion(ctx context.Context, name string, version int) (*TransitKey, error)
+	Encrypt(ctx context.Context, name string, plaintext []byte) (string, error)
+	Decrypt(ctx context.Context, name string, ciphertext string) ([]byte, error)
+	Rewrap(ctx context.Context, name string, ciphertext string) (string, error)
+	GenerateDataKey(ctx context.Context, name string) ([]byte, string, error)
+}
+
+type TransitServiceImpl struct {
+	Crypto CryptoService
+	Redis  RedisService
+	mu     sync.Mutex
+}
+
+func NewTransitService(crypto CryptoService, redis RedisService) TransitService {
+	return &TransitServiceImpl{Crypto: crypto, Redis: redis}
+}
+
+// CreateKey creates a transit key with a single version.
+func (t *TransitServiceImpl) CreateKey(ctx context.Context, name string) (*TransitKey, error) {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return nil, ErrInvalidKeyName
+	}
+
+	version, err := newTransitKeyVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &TransitKey{
+		Name:                 name,
+		LatestVersion:        1,
+		MinDecryptionVersion: 1,
+		Versions:             map[int]*TransitKeyVersion{1: version},
+		CreatedAt:            version.CreatedAt,
+	}
+	defer key.destroy()
+
+	encrypted, err := t.encryptKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := t.Redis.SetNX(ctx, TransitStoragePrefix+name, encrypted, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !stored {
+		return nil, ErrTransitKeyExists
+	}
+	return key, nil
+}
+
+// Key returns the transit key with the given name, without its key material.
+func (t *TransitServiceImpl) Key(ctx context.Context, name string) (*TransitKey, error) {
+	key, err := t.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	key.destroy()
+	return key, nil
+}
+
+// load decrypts the transit key with the given name. Callers must destroy it once they are done with it.
+func (t *TransitServiceImpl) load(ctx context.Context, name string) (*TransitKey, error) {
+	if err := helpers.ValidateKeyName(name); err != nil {
+		return nil, ErrTransitKeyNotFound
+	}
+
+	encrypted, err := t.Redis.Get(ctx, TransitStoragePrefix+name)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, ErrTransitKeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := t.Crypto.Decrypt(encrypted, TransitStoragePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []byte(plaintext)
+	defer clear(data)
+
+	var key TransitKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RotateKey adds a new version to the transit key, which is used to encrypt data from then on.
+func (t *TransitServiceImpl) RotateKey(ctx context.Context, name string) (*TransitKey, error) {
+	return t.update(ctx, name, func(key *TransitKey) error {
+		version, err := newTransitKeyVersion()
+		if err != nil {
+			return err
+		}
+		key.LatestVersion++
+		key.Versions[key.LatestVersion] = version
+		return nil
+	})
+}
+
+// SetMinDecryptionVersion disables decryption of data encrypted with key versions below the given version.
+// Versions below it are kept, so the minimum can be lowered again.
+func (t *TransitServiceImpl) SetMinDecryptionVersion(ctx context.Context, name string, version int) (*TransitKey, error) {
+	return t.update(ctx, name, func(key *TransitKey) error {
+		if version < 1 || version > key.LatestVersion {
+			return ErrInvalidMinDecryptionVersion
+		}
+		key.MinDecryptionVersion = version
+		return nil
+	})
+}
+
+// Encrypt encrypts the plaintext with the latest version of the transit key.
+func (t *TransitServiceImpl) Encrypt(ctx context.Context, name string, plaintext []byte) (string, error) {
+	key, err := t.load(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	defer key.destroy()
+	return encryptTransit(key, plaintext)
+}
+
+// Decrypt decrypts a ciphertext produced by the transit key.
+func (t *TransitServiceImpl) Decrypt(ctx context.Context, name string, ciphertext string) ([]byte, error) {
+	key, err := t.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer key.destroy()
+	return decryptTransit(key, ciphertext)
+}
+
+// Rewrap decrypts a ciphertext and encrypts it again with the latest version of the transit key,
+// without returning the plaintext.
+func (t *TransitServiceImpl) Rewrap(ctx context.Context, name string, ciphertext string) (string, error) {
+	key, err := t.load(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	defer key.destroy()
+
+	plaintext, err := decryptTransit(key, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	defer clear(plaintext)
+	return encryptTransit(key, plaintext)
+}
+
+// GenerateDataKey generates a random data key and returns it together with its ciphertext under the transit key.
+// Callers should clear the data key once they are done with it.
+func (t *TransitServiceImpl) GenerateDataKey(ctx context.Context, name string) ([]byte, string, error) {
+	key, err := t.load(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	defer key.destroy()
+
+	dataKey := make([]byte, TransitKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, "", err
+	}
+
+	ciphertext, err := encryptTransit(key, dataKey)
+	if err != nil {
+		clear(dataKey)
+		return nil, "", err
+	}
+	return dataKey, ciphertext, nil
+}
+
+// update applies fn to the stored transit key and stores the result, which is returned without its key material.
+func (t *TransitServiceImpl) update(ctx context.Context, name string, fn func(key *TransitKey) error) (*TransitKey, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key, err := t.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer key.destroy()
+	if err := fn(key); err != nil {
+		return nil, err
+	}
+
+	encrypted, err := t.encryptKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Redis.Set(ctx, TransitStoragePrefix+name, encrypted, 0); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// encryptKey serializes the transit key and encrypts it for storage.
+func (t *TransitServiceImpl) encryptKey(key *TransitKey) (string, error) {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	defer clear(data)
+	return t.Crypto.Encrypt(string(data), TransitStoragePrefix+key.Name)
+}
+
+// destroy overwrites and drops the key material of every version.
+func (k *TransitKey) destroy() {
+	for _, version := range k.Versions {
+		clear(version.Key)
+		version.Key = nil
+	}
+}
+
+// newTransitKeyVersion generates a random transit key version.
+func newTransitKeyVersion() (*TransitKeyVersion, error) {
+	value := make([]byte, TransitKeySize)
+	if _, err := rand.Read(value); err != nil {
+		return nil, err
+	}
+	return &TransitKeyVersion{Key: value, CreatedAt: time.Now().UTC()}, nil
+}
+
+// encryptTransit encrypts the plaintext with AES-GCM under the latest version of the transit key.
+func encryptTransit(key *TransitKey, plaintext []byte) (string, error) {
+	version, ok := key.Versions[key.LatestVersion]
+	if !ok {
+		return "", fmt.Errorf("latest version %d is missing from transit key", key.LatestVersion)
+	}
+
+	sealed, err := sealAESGCM(version.Key, plaintext)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:v%d:%s", TransitCiphertextPrefix, key.LatestVersion, base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// decryptTransit decrypts a transit ciphertext with the key version recorded in it.
+func decryptTransit(key *TransitKey, ciphertext string) ([]byte, error) {
+	parts := strings.SplitN(ciphertext, ":", 3)
+	if len(parts) != 3 || parts[0] != TransitCiphertextPrefix || !strings.HasPrefix(parts[1], "v") {
+		return nil, ErrInvalidTransitCiphertext
+	}
+
+	versionNumber, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil {
+		return nil, ErrInvalidTransitCiphertext
+	}
+	if versionNumber < key.MinDecryptionVersion {
+		return nil, ErrTransitKeyVersionDisabled
+	}
+
+	version, ok := key.Versions[versionNumber]
+	if !ok {
+		return nil, ErrInvalidTransitCiphertext
+	}
+
+	data, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidTransitCiphertext
+	}
+
+	plaintext, err := openAESGCM(version.Key, data)
+	if err != nil {
+		return nil, ErrInvalidTransitCiphertext
+	}
+	return plaintext, nil
+}
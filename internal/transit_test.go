@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTransitService returns a transit service over the given Redis, with its own keyring.
+func newTestTransitService(t *testing.T, redis RedisService) TransitService {
+	t.Helper()
+
+	crypto, _ := newTestCryptoService(t)
+	return NewTransitService(crypto, redis)
+}
+
+func TestTransitKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	transit := newTestTransitService(t, newFakeRedis())
+	_, err := transit.CreateKey(ctx, "orders")
+	require.NoError(t, err)
+
+	v1, err := transit.Encrypt(ctx, "orders", []byte("first"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(v1, "transit:v1:"))
+
+	_, err = transit.RotateKey(ctx, "orders")
+	require.NoError(t, err)
+	v2, err := transit.Encrypt(ctx, "orders", []byte("second"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(v2, "transit:v2:"))
+
+	t.Run("decrypts ciphertexts of every version", func(t *testing.T) {
+		for ciphertext, expected := range map[string]string{v1: "first", v2: "second"} {
+			plaintext, err := transit.Decrypt(ctx, "orders", ciphertext)
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(plaintext))
+		}
+	})
+
+	t.Run("rewraps ciphertexts with the latest version", func(t *testing.T) {
+		rewrapped, err := transit.Rewrap(ctx, "orders", v1)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(rewrapped, "transit:v2:"))
+
+		plaintext, err := transit.Decrypt(ctx, "orders", rewrapped)
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(plaintext))
+	})
+
+	t.Run("rejects ciphertexts below the minimum decryption version", func(t *testing.T) {
+		_, err := transit.SetMinDecryptionVersion(ctx, "orders", 2)
+		require.NoError(t, err)
+
+		_, err = transit.Decrypt(ctx, "orders", v1)
+		assert.ErrorIs(t, err, ErrTransitKeyVersionDisabled)
+		_, err = transit.Rewrap(ctx, "orders", v1)
+		assert.ErrorIs(t, err, ErrTransitKeyVersionDisabled)
+		_, err = transit.Decrypt(ctx, "orders", v2)
+		assert.NoError(t, err)
+
+		// Older versions are kept, so lowering the minimum again restores them
+		_, err = transit.SetMinDecryptionVersion(ctx, "orders", 1)
+		require.NoError(t, err)
+		_, err = transit.Decrypt(ctx, "orders", v1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects minimum decryption versions out of range", func(t *testing.T) {
+		for _, version := range []int{0, 3} {
+			_, err := transit.SetMinDecryptionVersion(ctx, "orders", version)
+			assert.ErrorIs(t, err, ErrInvalidMinDecryptionVersion)
+		}
+	})
+}
+
+func TestTransitKeysWithoutKeyMaterial(t *testing.T) {
+	ctx := context.Background()
+	transit := newTestTransitService(t, newFakeRedis())
+
+	created, err := transit.CreateKey(ctx, "orders")
+	require.NoError(t, err)
+	rotated, err := transit.RotateKey(ctx, "orders")
+	require.NoError(t, err)
+	configured, err := transit.SetMinDecryptionVersion(ctx, "orders", 2)
+	require.NoError(t, err)
+	key, err := transit.Key(ctx, "orders")
+	require.NoError(t, err)
+
+	for _, key := range []*TransitKey{created, rotated, configured, key} {
+		for _, version := range key.Versions {
+			assert.Nil(t, version.Key)
+		}
+	}
+	assert.Len(t, key.Versions, 2)
+
+	// The stored key material is untouched
+	ciphertext, err := transit.Encrypt(ctx, "orders", []byte("order"))
+	require.NoError(t, err)
+	plaintext, err := transit.Decrypt(ctx, "orders", ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "order", string(plaintext))
+}
+
+func TestTransitDataKey(t *testing.T) {
+	ctx := context.Background()
+	transit := newTestTransitService(t, newFakeRedis())
+	_, err := transit.CreateKey(ctx, "orders")
+	require.NoError(t, err)
+
+	dataKey, wrapped, err := transit.GenerateDataKey(ctx, "orders")
+	require.NoError(t, err)
+	assert.Len(t, dataKey, TransitKeySize)
+
+	unwrapped, err := transit.Decrypt(ctx, "orders", wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+}
+
+func TestTransitRejectsOtherCiphertexts(t *testing.T) {
+	ctx := context.Background()
+	transit := newTestTransitService(t, newFakeRedis())
+	for _, name := range []string{"orders", "payments"} {
+		_, err := transit.CreateKey(ctx, name)
+		require.NoError(t, err)
+	}
+	ciphertext, err := transit.Encrypt(ctx, "orders", []byte("order"))
+	require.NoError(t, err)
+
+	t.Run("rejects ciphertexts of another key", func(t *testing.T) {
+		_, err := transit.Decrypt(ctx, "payments", ciphertext)
+		assert.ErrorIs(t, err, ErrInvalidTransitCiphertext)
+		_, err = transit.Rewrap(ctx, "payments", ciphertext)
+		assert.ErrorIs(t, err, ErrInvalidTransitCiphertext)
+	})
+
+	t.Run("rejects malformed ciphertexts", func(t *testing.T) {
+		for _, malformed := range []string{
+			"",
+			"vault:v1:" + strings.SplitN(ciphertext, ":", 3)[2],
+			strings.Replace(ciphertext, "v1", "v9", 1),
+			ciphertext[:len(ciphertext)-4] + "AAA=",
+		} {
+			_, err := transit.Decrypt(ctx, "orders", malformed)
+			assert.ErrorIs(t, err, ErrInvalidTransitCiphertext, malformed)
+		}
+	})
+
+	t.Run("rejects unknown keys", func(t *testing.T) {
+		_, err := transit.Encrypt(ctx, "missing", []byte("order"))
+		assert.ErrorIs(t, err, ErrTransitKeyNotFound)
+	})
+}
@@ -12,14 +12,23 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
-	"go-secrets/config"
 	"go-secrets/helpers"
 	"go-secrets/internal"
 	"go-secrets/middlewares"
+	"go-secrets/models"
 	"go-secrets/routes"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -42,46 +51,229 @@ func main() {
 	_ = godotenv.Load()
 	redisURL, _ := helpers.GetEnv("REDIS_URL", "localhost:6379")
 	appPort, _ := helpers.GetEnv("APP_PORT", "8888")
+	masterKeyFile, _ := helpers.GetEnv("MASTER_KEY_FILE", "master.key")
+	encryptionAlgorithm, _ := helpers.GetEnv("ENCRYPTION_ALGORITHM", "aes256-gcm")
+	maxSecretSize, _ := helpers.GetEnv("MAX_SECRET_SIZE", "1048576")
+	maxBinarySecretSize, _ := helpers.GetEnv("MAX_BINARY_SECRET_SIZE", "67108864")
+	anonymousTokens, _ := helpers.GetEnv("ANONYMOUS_TOKENS", "false")
+	tlsCertFile, _ := helpers.GetEnv("TLS_CERT_FILE", "")
+	tlsKeyFile, _ := helpers.GetEnv("TLS_KEY_FILE", "")
+	tlsClientCAFile, _ := helpers.GetEnv("TLS_CLIENT_CA_FILE", "")
+	tlsRequireClientCert, _ := helpers.GetEnv("TLS_REQUIRE_CLIENT_CERT", "false")
 
-	// Set up Redis client
-	if err := internal.SetupRedis(redisURL); err != nil {
-		logger.LogError(context.Background(), "Failed to connect to Redis", "", err)
+	// Parse the size limits of JSON and binary secrets
+	maxValueSize, err := strconv.ParseInt(maxSecretSize, 10, 64)
+	if err != nil || maxValueSize <= 0 {
+		logger.LogError(context.Background(), "Invalid MAX_SECRET_SIZE, expected a positive number of bytes", "", err)
+		os.Exit(1)
+	}
+	maxBinarySize, err := strconv.ParseInt(maxBinarySecretSize, 10, 64)
+	if err != nil || maxBinarySize <= 0 {
+		logger.LogError(context.Background(), "Invalid MAX_BINARY_SECRET_SIZE, expected a positive number of bytes", "", err)
 		os.Exit(1)
 	}
 
-	// Generate and set server token
-	tokenService := internal.NewTokenService()
-	serverToken, err := tokenService.GenerateToken()
+	// Select the cipher used for new secrets
+	encryptionCipher, err := internal.CipherByName(encryptionAlgorithm)
 	if err != nil {
-		logger.LogError(context.Background(), "Failed to generate server token", "", err)
+		logger.LogError(context.Background(), "Invalid encryption algorithm", "", err)
 		os.Exit(1)
 	}
-	config.SetServerToken(serverToken)
 
-	// Initialize services
-	cryptoService := internal.NewCryptoService()
+	// Serve over TLS when a certificate is configured, verifying client certificates against the client CA bundle
+	var tlsConfig *tls.Config
+	if tlsCertFile != "" || tlsKeyFile != "" || tlsClientCAFile != "" {
+		tlsConfig, err = internal.LoadTLSConfig(tlsCertFile, tlsKeyFile, tlsClientCAFile, tlsRequireClientCert == "true")
+		if err != nil {
+			logger.LogError(context.Background(), "Invalid TLS configuration", "", err)
+			os.Exit(1)
+		}
+	}
+
+	// Set up Redis client
+	if err := internal.SetupRedis(redisURL); err != nil {
+		logger.LogError(context.Background(), "Failed to connect to Redis", "", err)
+		os.Exit(1)
+	}
 	redisClient, err := internal.GetRedisService()
 	if err != nil {
 		logger.LogError(context.Background(), "Failed to get Redis client", "", err)
 		os.Exit(1)
 	}
 
+	// Key material is kept in locked memory, which needs a high enough RLIMIT_MEMLOCK or CAP_IPC_LOCK
+	if err := internal.CheckMemoryLock(); err != nil {
+		logger.LogWarn(context.Background(), "Key material cannot be locked in memory", "", err)
+	}
+
+	keyringService := internal.NewKeyringService(redisClient)
+	cryptoService := internal.NewCryptoService(keyringService, encryptionCipher)
+	canaryService := internal.NewCanaryService(cryptoService, redisClient)
+	sealService := internal.NewSealService(redisClient, keyringService, canaryService)
+	tokenService := internal.NewTokenService(keyringService)
+
+	// Create the master key and keyring when started with the init command
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		initFlags := flag.NewFlagSet("init", flag.ExitOnError)
+		keyShares := initFlags.Int("shares", 0, "split the master key into this many unseal key shares instead of writing the master key file")
+		keyThreshold := initFlags.Int("threshold", 0, "number of unseal key shares required to unseal the server")
+		_ = initFlags.Parse(os.Args[2:])
+
+		if *keyShares > 0 {
+			shares, err := sealService.InitShares(context.Background(), *keyShares, *keyThreshold)
+			if err != nil {
+				logger.LogError(context.Background(), "Failed to initialize keyring", "", err)
+				os.Exit(1)
+			}
+			for i, share := range shares {
+				fmt.Printf("Unseal Key %d: %s\n", i+1, hex.EncodeToString(share))
+			}
+			if err := issueRootToken(context.Background(), tokenService, redisClient); err != nil {
+				logger.LogError(context.Background(), "Failed to create root token", "", err)
+				os.Exit(1)
+			}
+			fmt.Printf("\nThe server starts sealed. Submit %d of these %d keys to POST /sys/unseal to unseal it.\n", *keyThreshold, *keyShares)
+			return
+		}
+
+		masterKey, err := internal.CreateMasterKeyFile(masterKeyFile)
+		if err != nil {
+			logger.LogError(context.Background(), "Failed to create master key file", "", err)
+			os.Exit(1)
+		}
+		if err := keyringService.Init(context.Background(), masterKey); err != nil {
+			_ = os.Remove(masterKeyFile)
+			logger.LogError(context.Background(), "Failed to initialize keyring", "", err)
+			os.Exit(1)
+		}
+		if err := canaryService.Create(context.Background()); err != nil {
+			logger.LogError(context.Background(), "Failed to store key verification canary", "", err)
+			os.Exit(1)
+		}
+		if err := issueRootToken(context.Background(), tokenService, redisClient); err != nil {
+			logger.LogError(context.Background(), "Failed to create root token", "", err)
+			os.Exit(1)
+		}
+		logger.Log(context.Background(), slog.LevelInfo, "Keyring initialized", "", nil)
+		return
+	}
+
+	// Unseal with the master key file, unless the master key was split into key shares at init
+	if err := sealService.LoadConfig(context.Background()); err != nil {
+		logger.LogError(context.Background(), "Failed to load seal configuration", "", err)
+		os.Exit(1)
+	}
+	if sealService.Status().Type == internal.SealTypeKeyFile {
+		masterKey, err := internal.ReadMasterKeyFile(masterKeyFile)
+		if err != nil {
+			logger.LogError(context.Background(), "Failed to load master key", "", err)
+			os.Exit(1)
+		}
+		if _, err := sealService.Unseal(context.Background(), masterKey); errors.Is(err, internal.ErrCanaryMismatch) {
+			logger.LogError(context.Background(), "Refusing to start, the master key file and keyring do not match the data in Redis", "", err)
+			os.Exit(1)
+		} else if err != nil {
+			logger.LogError(context.Background(), "Failed to load keyring", "", err)
+			os.Exit(1)
+		}
+		status := sealService.Status()
+		logger.Log(context.Background(), slog.LevelInfo, fmt.Sprintf("Key material verified, active key version %d with fingerprint %s", status.KeyVersion, status.KeyFingerprint), "", nil)
+	} else {
+		logger.Log(context.Background(), slog.LevelInfo, "Server is sealed, submit unseal key shares to POST /sys/unseal", "", nil)
+	}
+
+	// Initialize services
+	rewrapService := internal.NewRewrapService(cryptoService, redisClient, keyringService, logger)
+	transitService := internal.NewTransitService(cryptoService, redisClient)
+	signingService := internal.NewSigningService(cryptoService, redisClient)
+	passwordPolicyService := internal.NewPasswordPolicyService(redisClient)
+	policyService := internal.NewPolicyService(redisClient)
+	blobService := internal.NewBlobService(cryptoService, redisClient, encryptionCipher, maxBinarySize)
+	statsService := internal.NewStatsService(redisClient)
+	appRoleService := internal.NewAppRoleService(redisClient)
+	jwtAuthService := internal.NewJWTAuthService(redisClient)
+	certAuthService := internal.NewCertAuthService(redisClient, tokenService)
+
 	// Set up router and middleware
 	router := gin.Default()
+
+	// Ignore X-Forwarded-For and X-Real-IP, so clients cannot choose the address that is logged and recorded for tokens
+	if err := router.SetTrustedProxies(nil); err != nil {
+		logger.LogError(context.Background(), "Failed to configure trusted proxies", "", err)
+		os.Exit(1)
+	}
 	router.Use(middlewares.RequestIDMiddleware())
 	router.Use(middlewares.LoggingMiddleware())
 
 	// Register routes
-	routes.TokenRoute(router, logger, cryptoService, redisClient, tokenService)
-	routes.SecretRoutes(router, logger, cryptoService, redisClient, tokenService)
+	routes.TokenRoute(router, logger, cryptoService, redisClient, tokenService, sealService, policyService, anonymousTokens == "true", certAuthService)
+	routes.SecretRoutes(router, logger, cryptoService, redisClient, tokenService, sealService, passwordPolicyService, blobService, maxValueSize, policyService, certAuthService)
+	routes.KeyringRoutes(router, logger, keyringService, rewrapService, redisClient, tokenService, sealService)
+	routes.SealRoutes(router, logger, redisClient, tokenService, sealService)
+	routes.TransitRoutes(router, logger, cryptoService, redisClient, tokenService, sealService, transitService, policyService, certAuthService)
+	routes.SigningRoutes(router, logger, cryptoService, redisClient, tokenService, sealService, signingService, policyService, certAuthService)
+	routes.PasswordPolicyRoutes(router, logger, redisClient, tokenService, sealService, passwordPolicyService)
+	routes.PolicyRoutes(router, logger, redisClient, tokenService, sealService, policyService)
+	routes.AppRoleRoutes(router, logger, redisClient, tokenService, sealService, appRoleService, policyService)
+	routes.JWTRoutes(router, logger, redisClient, tokenService, sealService, jwtAuthService, policyService)
+	routes.CertRoutes(router, logger, redisClient, tokenService, sealService, certAuthService, policyService)
+	routes.AdminRoutes(router, logger, redisClient, tokenService, sealService, statsService, models.ServerConfigResponse{
+		EncryptionAlgorithm: encryptionCipher.Name,
+		MaxSecretSize:       maxValueSize,
+		MaxBinarySecretSize: maxBinarySize,
+		AnonymousTokens:     anonymousTokens == "true",
+		TLS:                 tlsConfig != nil,
+		ClientCertAuth:      tlsConfig != nil && tlsConfig.ClientCAs != nil,
+	})
 
 	// Register Swagger route
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Start the server
-	serverAddress := fmt.Sprintf(":%s", appPort)
-	if err := router.Run(serverAddress); err != nil {
-		logger.LogError(context.Background(), "Failed to start server", "", err)
-		os.Exit(1)
+	server := &http.Server{Addr: fmt.Sprintf(":%s", appPort), Handler: router, TLSConfig: tlsConfig}
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			// The certificate is already loaded into the TLS configuration
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.LogError(context.Background(), "Failed to start server", "", err)
+			sealService.Seal()
+			os.Exit(1)
+		}
+	}()
+
+	// Seal on shutdown, so key material is wiped before the process exits
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-shutdownCtx.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.LogError(context.Background(), "Failed to shut down server gracefully", "", err)
+	}
+	sealService.Seal()
+	logger.Log(context.Background(), slog.LevelInfo, "Server sealed and stopped", "", nil)
+}
+
+// issueRootToken creates the root token, the only credential accepted for administration, and prints it once. It never
+// expires, so it should be stored safely.
+func issueRootToken(ctx context.Context, tokenService internal.TokenService, redisClient internal.RedisService) error {
+	rootToken, err := tokenService.GenerateToken()
+	if err != nil {
+		return err
 	}
+
+	record := internal.TokenRecord{Root: true, DisplayName: "root", CreatedAt: time.Now()}
+	stored, err := tokenService.StoreToken(ctx, rootToken, 0, record, redisClient)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nRoot Token: %s\nRoot Token Accessor: %s\n", rootToken, stored.Accessor)
+	return nil
 }
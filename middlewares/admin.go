@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"go-secrets/errors"
+	"go-secrets/internal"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminMiddlewareImpl struct {
+	Token internal.TokenService
+	Redis internal.RedisService
+}
+
+// AdminMiddleware restricts administrative routes to requests bearing a root token.
+func (a *AdminMiddlewareImpl) AdminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		parts := strings.Split(ctx.GetHeader("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			errors.ErrUnauthorized.WithRequestID(ctx).JSON(ctx)
+			ctx.Abort()
+			return
+		}
+
+		record, err := a.Token.LookupToken(ctx.Request.Context(), parts[1], a.Redis)
+		if err != nil {
+			errors.ErrUnauthorized.WithRequestID(ctx).JSON(ctx)
+			ctx.Abort()
+			return
+		}
+
+		if record.Accessor != "" {
+			ctx.Set("token_accessor", record.Accessor)
+			ctx.Request = ctx.Request.WithContext(internal.WithTokenAccessor(ctx.Request.Context(), record.Accessor))
+		}
+
+		if !record.Root {
+			errors.ErrRootTokenRequired.WithRequestID(ctx).JSON(ctx)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"go-secrets/internal"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminMiddlewareRequiresRootToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := &stubTokens{
+		records: map[string]*internal.TokenRecord{
+			"root":     {Accessor: "root", Root: true},
+			"policies": {Accessor: "policies", Policies: []string{"admin"}},
+		},
+		uses:    map[string]int{},
+		revoked: map[string]bool{},
+	}
+
+	admin := &AdminMiddlewareImpl{Token: tokens}
+	router := gin.New()
+	router.PUT("/sys/policies/:name", admin.AdminMiddleware(), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{"root token", "Bearer root", http.StatusOK},
+		{"token with policies", "Bearer policies", http.StatusForbidden},
+		{"unknown token", "Bearer unknown", http.StatusUnauthorized},
+		{"missing token", "", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPut, "/sys/policies/admin", nil)
+			request.Header.Set("Authorization", test.authorization)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, test.status, recorder.Code)
+		})
+	}
+}
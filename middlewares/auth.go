@@ -1,51 +1,141 @@
 package middlewares
 
 import (
+	"context"
+	stderrors "errors"
 	"go-secrets/errors"
+	"go-secrets/helpers"
 	"go-secrets/internal"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthMiddlewareImpl struct {
-	Crypto internal.CryptoService
-	Token  internal.TokenService
-	Redis  internal.RedisService
+	Crypto   internal.CryptoService
+	Token    internal.TokenService
+	Redis    internal.RedisService
+	Policies internal.PolicyService
+	Certs    internal.CertAuthService
+	// RequirePolicy denies tokens without policies, for routes using keys shared by all tokens rather than the
+	// token's own namespace
+	RequirePolicy bool
 }
 
 // AuthMiddleware handles the authorization of incoming requests by validating the Authorization header.
+// When Certs is set, requests without an Authorization header are authenticated by the session token of their
+// verified client certificate instead.
+// When Policies is set, tokens issued with policies may only perform the requests their policies allow.
+// When RequirePolicy is set, tokens without policies may perform none.
+// Every authenticated request its policies allow consumes a use of a use-limited token, which is revoked after its
+// last one.
 func (a *AuthMiddlewareImpl) AuthMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		authHeader := ctx.GetHeader("Authorization")
-		if authHeader == "" {
+		record, err := a.requestToken(ctx)
+		if err != nil {
 			errors.ErrUnauthorized.WithRequestID(ctx).JSON(ctx)
 			ctx.Abort()
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			errors.ErrUnauthorized.WithRequestID(ctx).JSON(ctx)
+		// Identify the token by its accessor in logs, never by its HMAC
+		if record.Accessor != "" {
+			ctx.Set("token_accessor", record.Accessor)
+			ctx.Request = ctx.Request.WithContext(internal.WithTokenAccessor(ctx.Request.Context(), record.Accessor))
+		}
+
+		// Root tokens never expire, so they are kept to the administrative routes
+		if record.Root {
+			errors.ErrRootTokenNotAllowed.WithRequestID(ctx).JSON(ctx)
 			ctx.Abort()
 			return
 		}
 
-		token := parts[1]
-		tokenHMAC, err := a.Crypto.GenerateHMAC(token)
-		if err != nil {
-			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+		// Check policies first, so forbidden requests do not consume a use
+		if a.RequirePolicy && len(record.Policies) == 0 {
+			errors.ErrForbidden.WithRequestID(ctx).JSON(ctx)
 			ctx.Abort()
 			return
 		}
+		if a.Policies != nil && len(record.Policies) > 0 {
+			allowed, err := a.Policies.Allowed(ctx.Request.Context(), record.Policies, requestPolicyPath(ctx), requestCapability(ctx))
+			if err != nil {
+				errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+				ctx.Abort()
+				return
+			}
+			if !allowed {
+				errors.ErrForbidden.WithRequestID(ctx).JSON(ctx)
+				ctx.Abort()
+				return
+			}
+		}
 
-		valid, err := a.Token.ValidateToken(tokenHMAC, a.Redis)
-		if !valid || err != nil {
+		remainingUses, err := a.Token.UseToken(ctx.Request.Context(), record, a.Redis)
+		if stderrors.Is(err, internal.ErrTokenExhausted) {
 			errors.ErrUnauthorized.WithRequestID(ctx).JSON(ctx)
 			ctx.Abort()
 			return
+		} else if err != nil {
+			errors.ErrInternalServer.WithRequestID(ctx).JSON(ctx)
+			ctx.Abort()
+			return
+		}
+		if record.NumUses > 0 {
+			ctx.Set("token_remaining_uses", remainingUses)
+			if remainingUses == 0 {
+				// Revoke the token once its last request is done, even if the client went away
+				defer func() {
+					_ = a.Token.RevokeToken(context.WithoutCancel(ctx.Request.Context()), record, a.Redis)
+				}()
+			}
 		}
 
+		ctx.Set("token_hmac", record.HMAC)
+		ctx.Set("token_namespace", record.Namespace)
+
 		ctx.Next()
 	}
 }
+
+// requestToken returns the record of the token a request is made with: the Bearer token of its Authorization header,
+// or without one the session token of its verified client certificate.
+func (a *AuthMiddlewareImpl) requestToken(ctx *gin.Context) (*internal.TokenRecord, error) {
+	authHeader := ctx.GetHeader("Authorization")
+	if authHeader == "" {
+		cert := helpers.VerifiedClientCertificate(ctx.Request)
+		if a.Certs == nil || cert == nil {
+			return nil, internal.ErrTokenNotFound
+		}
+		return a.Certs.SessionToken(ctx.Request.Context(), cert, ctx.ClientIP())
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, internal.ErrTokenNotFound
+	}
+	return a.Token.LookupToken(ctx.Request.Context(), parts[1], a.Redis)
+}
+
+// requestPolicyPath returns the path policies are matched against: the request path without its leading slash,
+// e.g. secret/ci/db or transit/encrypt/orders.
+func requestPolicyPath(ctx *gin.Context) string {
+	return strings.TrimPrefix(ctx.Request.URL.Path, "/")
+}
+
+// requestCapability returns the capability a request needs: list for GET requests with list=true, read for other
+// GET requests, delete for DELETE requests and write for everything else.
+func requestCapability(ctx *gin.Context) string {
+	switch ctx.Request.Method {
+	case http.MethodGet, http.MethodHead:
+		if ctx.Query("list") == "true" {
+			return helpers.CapabilityList
+		}
+		return helpers.CapabilityRead
+	case http.MethodDelete:
+		return helpers.CapabilityDelete
+	default:
+		return helpers.CapabilityWrite
+	}
+}
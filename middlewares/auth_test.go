@@ -0,0 +1,134 @@
+package middlewares
+
+import (
+	"context"
+	"go-secrets/helpers"
+	"go-secrets/internal"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubTokens resolves tokens to fixed records, counts the uses they consume and records their revocation.
+type stubTokens struct {
+	internal.TokenService
+	records map[string]*internal.TokenRecord
+	uses    map[string]int
+	revoked map[string]bool
+}
+
+func (s *stubTokens) LookupToken(ctx context.Context, token string, r internal.RedisService) (*internal.TokenRecord, error) {
+	if record, ok := s.records[token]; ok {
+		return record, nil
+	}
+	return nil, internal.ErrTokenNotFound
+}
+
+func (s *stubTokens) UseToken(ctx context.Context, record *internal.TokenRecord, r internal.RedisService) (int, error) {
+	s.uses[record.Accessor]++
+	return max(record.NumUses-s.uses[record.Accessor], 0), nil
+}
+
+func (s *stubTokens) RevokeToken(ctx context.Context, record *internal.TokenRecord, r internal.RedisService) error {
+	s.revoked[record.Accessor] = true
+	return nil
+}
+
+// stubPolicies grants the capabilities of the named policies on the paths matching their globs.
+type stubPolicies struct {
+	internal.PolicyService
+	rules map[string]map[string]string
+}
+
+func (s *stubPolicies) Allowed(ctx context.Context, names []string, path string, capability string) (bool, error) {
+	for _, name := range names {
+		for glob, granted := range s.rules[name] {
+			if granted == capability && helpers.MatchPathGlob(glob, path) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func TestAuthMiddlewareRequirePolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := &stubTokens{
+		records: map[string]*internal.TokenRecord{
+			"plain":   {Accessor: "plain"},
+			"granted": {Accessor: "granted", Policies: []string{"orders"}},
+		},
+		uses:    map[string]int{},
+		revoked: map[string]bool{},
+	}
+	policies := &stubPolicies{rules: map[string]map[string]string{
+		"orders": {"transit/*/orders": helpers.CapabilityWrite},
+	}}
+
+	shared := &AuthMiddlewareImpl{Token: tokens, Policies: policies, RequirePolicy: true}
+	own := &AuthMiddlewareImpl{Token: tokens, Policies: policies}
+	router := gin.New()
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+	router.POST("/transit/decrypt/:name", shared.AuthMiddleware(), ok)
+	router.POST("/signing/verify/:name", own.AuthMiddleware(), ok)
+
+	tests := []struct {
+		name   string
+		token  string
+		path   string
+		status int
+	}{
+		{"token without policies cannot use shared keys", "plain", "/transit/decrypt/orders", http.StatusForbidden},
+		{"token without policies can verify", "plain", "/signing/verify/releases", http.StatusOK},
+		{"granted token can use its key", "granted", "/transit/decrypt/orders", http.StatusOK},
+		{"granted token cannot use other keys", "granted", "/transit/decrypt/payments", http.StatusForbidden},
+		{"unknown token", "unknown", "/transit/decrypt/orders", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader("{}"))
+			request.Header.Set("Authorization", "Bearer "+test.token)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, test.status, recorder.Code)
+		})
+	}
+}
+
+func TestAuthMiddlewareForbiddenRequestsKeepUses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := &stubTokens{
+		records: map[string]*internal.TokenRecord{
+			"limited": {Accessor: "limited", Policies: []string{"orders"}, NumUses: 1},
+		},
+		uses:    map[string]int{},
+		revoked: map[string]bool{},
+	}
+	policies := &stubPolicies{rules: map[string]map[string]string{
+		"orders": {"secret/orders/*": helpers.CapabilityRead},
+	}}
+
+	auth := &AuthMiddlewareImpl{Token: tokens, Policies: policies}
+	router := gin.New()
+	router.GET("/secret/*key", auth.AuthMiddleware(), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	request := func(path string) int {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		request.Header.Set("Authorization", "Bearer limited")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, request("/secret/payments/db"))
+	assert.Equal(t, 0, tokens.uses["limited"])
+	assert.False(t, tokens.revoked["limited"])
+
+	assert.Equal(t, http.StatusOK, request("/secret/orders/db"))
+	assert.Equal(t, 1, tokens.uses["limited"])
+	assert.True(t, tokens.revoked["limited"])
+}
@@ -30,6 +30,7 @@ func LoggingMiddleware() gin.HandlerFunc {
 			slog.String("path", ctx.Request.URL.Path),
 			slog.String("remote_ip", ctx.ClientIP()),
 			slog.String("request_id", ctx.GetHeader("X-Request-ID")),
+			slog.String("token_accessor", ctx.GetString("token_accessor")),
 		)
 	}
 }
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"go-secrets/errors"
+	"go-secrets/internal"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SealMiddlewareImpl struct {
+	Seal internal.SealService
+}
+
+// SealMiddleware rejects requests that need the keyring while the server is sealed.
+func (s *SealMiddlewareImpl) SealMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if s.Seal.Sealed() {
+			errors.ErrSealed.WithRequestID(ctx).JSON(ctx)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -1,8 +1,188 @@
 package models
 
-// StoreSecretRequest represents the request payload for storing a secret, containing the value of the secret.
+import "go-secrets/helpers"
+
+// StoreSecretRequest represents the request payload for storing a secret, containing either the value of the secret
+// or a spec for generating it on the server. Values encrypted by the client are stored without server-side encryption.
 // @Description Store secret request format
 // @Example { "value": "my_secret_value" }
 type StoreSecretRequest struct {
-	Value string `json:"value" binding:"required"`
+	Value           string              `json:"value"`
+	Generate        *GenerateSecretSpec `json:"generate,omitempty"`
+	ClientEncrypted bool                `json:"client_encrypted,omitempty"` // The value was encrypted by the client and is stored as an opaque blob
+}
+
+// GenerateSecretSpec describes how the server generates a secret value, either by referencing a named password
+// policy or with an inline policy.
+// @Description Secret generation spec format
+// @Example { "length": 32, "classes": ["lowercase", "uppercase", "digits"], "min_counts": { "digits": 2 } }
+type GenerateSecretSpec struct {
+	Policy string `json:"policy,omitempty"` // Name of a stored password policy
+	helpers.PasswordPolicy
+}
+
+// CreateChildTokenRequest represents the request payload for creating a child token. The TTL defaults to the
+// shorter of the default TTL and the parent's remaining TTL, the max TTL to the parent's remaining max TTL, and the
+// use limit and policies to the parent's remaining uses and policies.
+// @Description Create child token request format
+// @Example { "ttl": 300, "max_ttl": 600, "policies": ["ci-read"], "display_name": "deploy", "labels": { "team": "ci" } }
+type CreateChildTokenRequest struct {
+	TTL         int               `json:"ttl,omitempty"`      // TTL in seconds, at most the parent's remaining TTL
+	MaxTTL      int               `json:"max_ttl,omitempty"`  // TTL in seconds the child can be renewed up to, at most the parent's remaining max TTL
+	NumUses     int               `json:"num_uses,omitempty"` // Use limit, at most the parent's remaining uses if it has a limit
+	Policies    []string          `json:"policies,omitempty"` // Names of policies, a subset of the parent's
+	Orphan      bool              `json:"orphan,omitempty"`   // The child is not revoked along with its parent
+	DisplayName string            `json:"display_name,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// RenewTokenRequest represents the request payload for renewing a token. The increment defaults to the default TTL.
+// @Description Renew token request format
+// @Example { "increment": 900 }
+type RenewTokenRequest struct {
+	Increment int `json:"increment,omitempty"` // New TTL in seconds from now, bounded by the token's max TTL
+}
+
+// AppRoleRequest represents the request payload for creating or updating an AppRole. The token TTL and max TTL
+// default to those of tokens issued by GET /token.
+// @Description AppRole request format
+// @Example { "policies": ["ci-read"], "token_ttl": 600, "token_max_ttl": 3600, "secret_id_ttl": 86400, "secret_id_num_uses": 10, "secret_id_bound_cidrs": ["10.0.0.0/8"] }
+type AppRoleRequest struct {
+	Policies           []string `json:"policies,omitempty"`              // Names of the policies attached to issued tokens
+	TokenTTL           int      `json:"token_ttl,omitempty"`             // TTL in seconds of issued tokens
+	TokenMaxTTL        int      `json:"token_max_ttl,omitempty"`         // TTL in seconds issued tokens can be renewed up to
+	TokenNumUses       int      `json:"token_num_uses,omitempty"`        // Use limit of issued tokens, 0 for unlimited
+	SecretIDTTL        int      `json:"secret_id_ttl,omitempty"`         // Default and maximum TTL in seconds of secret IDs, 0 for no expiry
+	SecretIDNumUses    int      `json:"secret_id_num_uses,omitempty"`    // Default and maximum logins per secret ID, 0 for unlimited
+	SecretIDBoundCIDRs []string `json:"secret_id_bound_cidrs,omitempty"` // Addresses any secret ID of the role may log in from
+}
+
+// IssueSecretIDRequest represents the request payload for issuing a secret ID for an AppRole. The TTL and use limit
+// default to the role's and cannot exceed them.
+// @Description Issue secret ID request format
+// @Example { "ttl": 3600, "num_uses": 1, "cidr_list": ["10.0.1.0/24"] }
+type IssueSecretIDRequest struct {
+	TTL      int      `json:"ttl,omitempty"`       // TTL in seconds
+	NumUses  int      `json:"num_uses,omitempty"`  // Number of logins before the secret ID is deleted
+	CIDRList []string `json:"cidr_list,omitempty"` // Addresses the secret ID may log in from, in addition to the role's
+}
+
+// AppRoleLoginRequest represents the request payload for logging in with an AppRole.
+// @Description AppRole login request format
+// @Example { "role_id": "8f1c2a9e0b7d4c3f6a5e2d1c0b9a8f7e", "secret_id": "3b5d...c2a1" }
+type AppRoleLoginRequest struct {
+	RoleID   string `json:"role_id" binding:"required"`
+	SecretID string `json:"secret_id" binding:"required"`
+}
+
+// JWTIssuerRequest represents the request payload for configuring a trusted JWT issuer. Exactly one of the JWKS URL
+// and JWKS file must be set.
+// @Description JWT issuer request format
+// @Example { "issuer": "https://token.actions.githubusercontent.com", "jwks_url": "https://token.actions.githubusercontent.com/.well-known/jwks" }
+type JWTIssuerRequest struct {
+	Issuer   string `json:"issuer" binding:"required"` // Expected iss claim
+	JWKSURL  string `json:"jwks_url,omitempty"`        // URL the key set is fetched from
+	JWKSFile string `json:"jwks_file,omitempty"`       // Path of a key set file on the server
+}
+
+// JWTRoleRequest represents the request payload for creating or updating a JWT role. Bound claims and claim mappings
+// are keyed by claim name, or by a JSON pointer such as /kubernetes.io/namespace for nested claims. The token TTL and
+// max TTL default to those of tokens issued by GET /token.
+// @Description JWT role request format
+// @Example { "issuer": "github", "bound_audiences": ["go-secrets"], "bound_claims": { "repository": ["acme/*"] }, "claim_mappings": { "repository": "repository" }, "policies": ["ci-read"], "token_ttl": 600 }
+type JWTRoleRequest struct {
+	Issuer         string              `json:"issuer" binding:"required"` // Name of the JWT issuer
+	BoundAudiences []string            `json:"bound_audiences,omitempty"` // Required when the JWTs have an aud claim
+	BoundSubject   string              `json:"bound_subject,omitempty"`   // Required sub claim
+	BoundClaims    map[string][]string `json:"bound_claims,omitempty"`    // Allowed values of claims, which may contain * globs
+	UserClaim      string              `json:"user_claim,omitempty"`      // Claim used as the token's display name, defaults to sub
+	ClaimMappings  map[string]string   `json:"claim_mappings,omitempty"`  // Claims copied into token labels, mapped to the label key
+	Policies       []string            `json:"policies,omitempty"`        // Names of the policies attached to issued tokens
+	TokenTTL       int                 `json:"token_ttl,omitempty"`       // TTL in seconds of issued tokens
+	TokenMaxTTL    int                 `json:"token_max_ttl,omitempty"`   // TTL in seconds issued tokens can be renewed up to
+	TokenNumUses   int                 `json:"token_num_uses,omitempty"`  // Use limit of issued tokens, 0 for unlimited
+}
+
+// JWTLoginRequest represents the request payload for logging in with a JWT.
+// @Description JWT login request format
+// @Example { "role": "ci", "jwt": "eyJhbGciOiJSUzI1NiIsImtpZCI6..." }
+type JWTLoginRequest struct {
+	Role string `json:"role" binding:"required"`
+	JWT  string `json:"jwt" binding:"required"`
+}
+
+// CertRoleRequest represents the request payload for creating or updating a certificate role. Every non-empty list of
+// allowed names must match the client certificate, and its entries may contain * globs that also match dots. The
+// token TTL and max TTL default to those of tokens issued by GET /token.
+// @Description Certificate role request format
+// @Example { "allowed_common_names": ["*.ci.internal"], "allowed_uri_sans": ["spiffe://example.org/ns/ci/*"], "policies": ["ci-read"], "token_ttl": 600 }
+type CertRoleRequest struct {
+	AllowedCommonNames         []string `json:"allowed_common_names,omitempty"`         // Allowed subject common names
+	AllowedOrganizationalUnits []string `json:"allowed_organizational_units,omitempty"` // Allowed subject organizational units
+	AllowedDNSSANs             []string `json:"allowed_dns_sans,omitempty"`             // Allowed DNS name SANs
+	AllowedEmailSANs           []string `json:"allowed_email_sans,omitempty"`           // Allowed email address SANs
+	AllowedURISANs             []string `json:"allowed_uri_sans,omitempty"`             // Allowed URI SANs
+	Policies                   []string `json:"policies,omitempty"`                     // Names of the policies attached to issued tokens
+	TokenTTL                   int      `json:"token_ttl,omitempty"`                    // TTL in seconds of issued tokens
+	TokenMaxTTL                int      `json:"token_max_ttl,omitempty"`                // TTL in seconds tokens issued by logging in can be renewed up to
+	TokenNumUses               int      `json:"token_num_uses,omitempty"`               // Use limit of tokens issued by logging in, 0 for unlimited
+}
+
+// CertLoginRequest represents the optional request payload for logging in with a client certificate. Without a role,
+// the first role by name the certificate matches is used.
+// @Description Certificate login request format
+// @Example { "role": "ci" }
+type CertLoginRequest struct {
+	Role string `json:"role,omitempty"`
+}
+
+// UnsealRequest represents the request payload for submitting an unseal key, or discarding the key shares submitted so far.
+// @Description Unseal request format
+// @Example { "key": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a0801" }
+type UnsealRequest struct {
+	Key   string `json:"key"`
+	Reset bool   `json:"reset"`
+}
+
+// TransitEncryptRequest represents the request payload for encrypting data with a transit key.
+// @Description Transit encrypt request format
+// @Example { "plaintext": "aGVsbG8gd29ybGQ=" }
+type TransitEncryptRequest struct {
+	Plaintext string `json:"plaintext" binding:"required"` // Base64 encoded data
+}
+
+// TransitCiphertextRequest represents the request payload for decrypting or rewrapping a transit ciphertext.
+// @Description Transit ciphertext request format
+// @Example { "ciphertext": "transit:v1:q2LlJ7Tz..." }
+type TransitCiphertextRequest struct {
+	Ciphertext string `json:"ciphertext" binding:"required"`
+}
+
+// TransitKeyConfigRequest represents the request payload for configuring a transit key.
+// @Description Transit key configuration request format
+// @Example { "min_decryption_version": 2 }
+type TransitKeyConfigRequest struct {
+	MinDecryptionVersion int `json:"min_decryption_version" binding:"required"`
+}
+
+// CreateSigningKeyRequest represents the request payload for creating a signing key.
+// @Description Create signing key request format
+// @Example { "type": "ed25519" }
+type CreateSigningKeyRequest struct {
+	Type string `json:"type" binding:"required"` // ed25519 or ecdsa-p256
+}
+
+// SignRequest represents the request payload for signing data.
+// @Description Sign request format
+// @Example { "input": "aGVsbG8gd29ybGQ=" }
+type SignRequest struct {
+	Input string `json:"input" binding:"required"` // Base64 encoded data
+}
+
+// VerifyRequest represents the request payload for verifying a signature.
+// @Description Verify request format
+// @Example { "input": "aGVsbG8gd29ybGQ=", "signature": "MEUCIQD..." }
+type VerifyRequest struct {
+	Input     string `json:"input" binding:"required"`     // Base64 encoded data
+	Signature string `json:"signature" binding:"required"` // Base64 encoded signature
 }
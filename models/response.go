@@ -1,31 +1,296 @@
 package models
 
+import "time"
+
 // GetSecretResponse represents the response payload for retrieving a secret, containing the secret's value and its time-to-live (TTL).
 // @Description Get secret response format
 // @Example { "value": "my_secret_value", "ttl": 3600 }
 type GetSecretResponse struct {
-	Value string `json:"value"`
-	TTL   int    `json:"ttl"`
+	Value           string `json:"value"`
+	TTL             int    `json:"ttl"`
+	ClientEncrypted bool   `json:"client_encrypted,omitempty"`
+}
+
+// ListSecretsResponse represents the response payload for listing secrets, containing the keys under a prefix.
+// @Description List secrets response format
+// @Example { "keys": ["ci/cache", "ci/db"] }
+type ListSecretsResponse struct {
+	Keys []string `json:"keys"`
 }
 
 // StoreSecretResponse represents the response payload for storing a secret, containing the generated key and its time-to-live (TTL).
+// A value generated by the server is returned once in this response; binary secrets report their content type and size.
 // @Description Store secret response format
 // @Example { "key": "abc123", "ttl": 3600 }
 type StoreSecretResponse struct {
-	Key string `json:"key"`
-	TTL int    `json:"ttl"`
+	Key         string `json:"key"`
+	TTL         int    `json:"ttl"`
+	Value       string `json:"value,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
 }
 
 // IssueTokenResponse represents the response payload for issuing a token, containing the token string and its time-to-live (TTL).
 // @Description Issue token response format
-// @Example { "token": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9", "ttl": 7200 }
+// @Example { "token": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9", "accessor": "8f1c2a9e0b7d4c3f6a5e2d1c0b9a8f7e", "ttl": 900, "max_ttl": 3600, "policies": ["ci-read"] }
 type IssueTokenResponse struct {
-	Token string `json:"token"`
-	TTL   int    `json:"ttl"`
+	Token    string   `json:"token"`
+	Accessor string   `json:"accessor,omitempty"`
+	TTL      int      `json:"ttl"`
+	MaxTTL   int      `json:"max_ttl,omitempty"`
+	NumUses  int      `json:"num_uses,omitempty"`
+	Policies []string `json:"policies,omitempty"`
+	Orphan   bool     `json:"orphan,omitempty"`
+}
+
+// TokenAccessorsResponse represents the response payload listing the accessors of all tokens.
+// @Description Token accessors response format
+// @Example { "accessors": ["8f1c2a9e0b7d4c3f6a5e2d1c0b9a8f7e"] }
+type TokenAccessorsResponse struct {
+	Accessors []string `json:"accessors"`
+}
+
+// RenewTokenResponse represents the response payload for renewing a token, containing its new time-to-live (TTL).
+// @Description Renew token response format
+// @Example { "ttl": 900 }
+type RenewTokenResponse struct {
+	TTL int `json:"ttl"`
 }
 
-// TokenValidationResponse represents the response payload for validating a token.
+// TokenValidationResponse represents the response payload for validating a token. Use-limited tokens report the uses
+// left after the validation request itself.
 // @Description Token validation response format
+// @Example { "valid": true, "remaining_uses": 4 }
 type TokenValidationResponse struct {
+	Valid         bool `json:"valid"`
+	RemainingUses *int `json:"remaining_uses,omitempty"`
+}
+
+// TokenLookupResponse represents the response payload describing the authenticated token, without the token itself.
+// Use-limited tokens report the uses left after the lookup request itself.
+// @Description Token lookup response format
+// @Example { "accessor": "8f1c2a9e0b7d4c3f6a5e2d1c0b9a8f7e", "display_name": "deploy", "labels": { "team": "ci" }, "policies": ["ci-read"], "created_at": "2025-01-01T12:00:00Z", "creator_ip": "10.0.0.5", "ttl": 840, "original_ttl": 900, "max_ttl": 3600, "expires_at": "2025-01-01T12:15:00Z", "requests": 3, "secrets": 2 }
+type TokenLookupResponse struct {
+	Accessor      string            `json:"accessor,omitempty"`
+	DisplayName   string            `json:"display_name,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Policies      []string          `json:"policies,omitempty"`
+	CreatedAt     *time.Time        `json:"created_at,omitempty"`
+	CreatorIP     string            `json:"creator_ip,omitempty"`
+	TTL           int               `json:"ttl"`                    // Remaining TTL in seconds, 0 for root tokens
+	OriginalTTL   int               `json:"original_ttl,omitempty"` // TTL the token was issued with
+	MaxTTL        int               `json:"max_ttl,omitempty"`
+	ExpiresAt     *time.Time        `json:"expires_at,omitempty"`
+	NumUses       int               `json:"num_uses,omitempty"`
+	RemainingUses *int              `json:"remaining_uses,omitempty"`
+	Requests      int               `json:"requests"` // Requests the token authenticated, including this one
+	Secrets       int               `json:"secrets"`
+	Child         bool              `json:"child,omitempty"` // The token is revoked along with the token that created it
+	Root          bool              `json:"root,omitempty"`  // The token never expires and may only be used for administration
+}
+
+// AppRoleResponse represents the response payload describing an AppRole, including the role ID machines log in with.
+// @Description AppRole format
+// @Example { "name": "ci", "role_id": "8f1c2a9e0b7d4c3f6a5e2d1c0b9a8f7e", "policies": ["ci-read"], "token_ttl": 600, "token_max_ttl": 3600, "secret_id_ttl": 86400, "created_at": "2025-01-01T12:00:00Z" }
+type AppRoleResponse struct {
+	Name               string    `json:"name"`
+	RoleID             string    `json:"role_id"`
+	Policies           []string  `json:"policies,omitempty"`
+	TokenTTL           int       `json:"token_ttl"`
+	TokenMaxTTL        int       `json:"token_max_ttl"`
+	TokenNumUses       int       `json:"token_num_uses,omitempty"`
+	SecretIDTTL        int       `json:"secret_id_ttl,omitempty"`
+	SecretIDNumUses    int       `json:"secret_id_num_uses,omitempty"`
+	SecretIDBoundCIDRs []string  `json:"secret_id_bound_cidrs,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// SecretIDResponse represents the response payload for issuing a secret ID, which is only returned once.
+// @Description Secret ID response format
+// @Example { "secret_id": "3b5d...c2a1", "ttl": 3600, "num_uses": 1 }
+type SecretIDResponse struct {
+	SecretID string   `json:"secret_id"`
+	TTL      int      `json:"ttl,omitempty"`
+	NumUses  int      `json:"num_uses,omitempty"`
+	CIDRList []string `json:"cidr_list,omitempty"`
+}
+
+// JWTIssuerResponse represents the response payload describing a trusted JWT issuer.
+// @Description JWT issuer format
+// @Example { "name": "github", "issuer": "https://token.actions.githubusercontent.com", "jwks_url": "https://token.actions.githubusercontent.com/.well-known/jwks" }
+type JWTIssuerResponse struct {
+	Name     string `json:"name"`
+	Issuer   string `json:"issuer"`
+	JWKSURL  string `json:"jwks_url,omitempty"`
+	JWKSFile string `json:"jwks_file,omitempty"`
+}
+
+// JWTRoleResponse represents the response payload describing a JWT role.
+// @Description JWT role format
+// @Example { "name": "ci", "issuer": "github", "bound_audiences": ["go-secrets"], "bound_claims": { "repository": ["acme/*"] }, "user_claim": "sub", "policies": ["ci-read"], "token_ttl": 600, "token_max_ttl": 3600 }
+type JWTRoleResponse struct {
+	Name           string              `json:"name"`
+	Issuer         string              `json:"issuer"`
+	BoundAudiences []string            `json:"bound_audiences,omitempty"`
+	BoundSubject   string              `json:"bound_subject,omitempty"`
+	BoundClaims    map[string][]string `json:"bound_claims,omitempty"`
+	UserClaim      string              `json:"user_claim"`
+	ClaimMappings  map[string]string   `json:"claim_mappings,omitempty"`
+	Policies       []string            `json:"policies,omitempty"`
+	TokenTTL       int                 `json:"token_ttl"`
+	TokenMaxTTL    int                 `json:"token_max_ttl"`
+	TokenNumUses   int                 `json:"token_num_uses,omitempty"`
+}
+
+// CertRoleResponse represents the response payload describing a certificate role.
+// @Description Certificate role format
+// @Example { "name": "ci", "allowed_common_names": ["*.ci.internal"], "allowed_uri_sans": ["spiffe://example.org/ns/ci/*"], "policies": ["ci-read"], "token_ttl": 600, "token_max_ttl": 3600 }
+type CertRoleResponse struct {
+	Name                       string   `json:"name"`
+	AllowedCommonNames         []string `json:"allowed_common_names,omitempty"`
+	AllowedOrganizationalUnits []string `json:"allowed_organizational_units,omitempty"`
+	AllowedDNSSANs             []string `json:"allowed_dns_sans,omitempty"`
+	AllowedEmailSANs           []string `json:"allowed_email_sans,omitempty"`
+	AllowedURISANs             []string `json:"allowed_uri_sans,omitempty"`
+	Policies                   []string `json:"policies,omitempty"`
+	TokenTTL                   int      `json:"token_ttl"`
+	TokenMaxTTL                int      `json:"token_max_ttl"`
+	TokenNumUses               int      `json:"token_num_uses,omitempty"`
+}
+
+// StoreStatsResponse represents the response payload counting the entries in the store by kind.
+// @Description Store statistics format
+// @Example { "keys": 42, "tokens": 3, "secrets": 12, "blob_chunks": 4, "policies": 2, "password_policies": 1, "transit_keys": 1, "signing_keys": 1, "approles": 1, "key_version": 2 }
+type StoreStatsResponse struct {
+	Keys             int `json:"keys"`
+	Tokens           int `json:"tokens"`
+	Secrets          int `json:"secrets"`
+	BlobChunks       int `json:"blob_chunks"`
+	Policies         int `json:"policies"`
+	PasswordPolicies int `json:"password_policies"`
+	TransitKeys      int `json:"transit_keys"`
+	SigningKeys      int `json:"signing_keys"`
+	AppRoles         int `json:"approles"`
+	KeyVersion       int `json:"key_version,omitempty"` // Active root key version, while unsealed
+}
+
+// ServerConfigResponse represents the response payload describing the effective server configuration.
+// @Description Server configuration format
+// @Example { "encryption_algorithm": "aes256-gcm", "max_secret_size": 1048576, "max_binary_secret_size": 67108864, "seal_type": "key_file", "anonymous_tokens": false, "tls": true, "client_cert_auth": true }
+type ServerConfigResponse struct {
+	EncryptionAlgorithm string `json:"encryption_algorithm"`
+	MaxSecretSize       int64  `json:"max_secret_size"`
+	MaxBinarySecretSize int64  `json:"max_binary_secret_size"`
+	SealType            string `json:"seal_type"`
+	AnonymousTokens     bool   `json:"anonymous_tokens"` // GET /token issues tokens without authentication
+	TLS                 bool   `json:"tls"`
+	ClientCertAuth      bool   `json:"client_cert_auth"` // Client certificates are verified against a CA bundle
+}
+
+// KeyringKeyResponse represents a single root key version in the keyring, without its key material.
+// @Description Keyring key format
+type KeyringKeyResponse struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Active    bool      `json:"active"`
+}
+
+// RewrapStatusResponse represents the progress of the background job that re-encrypts stored data with the active key.
+// @Description Rewrap job status format
+// @Example { "running": false, "target_version": 2, "scanned": 120, "reencrypted": 80, "failed": 0, "pending_tokens": { "1": 3 } }
+type RewrapStatusResponse struct {
+	Running       bool           `json:"running"`
+	TargetVersion int            `json:"target_version,omitempty"`
+	StartedAt     *time.Time     `json:"started_at,omitempty"`
+	FinishedAt    *time.Time     `json:"finished_at,omitempty"`
+	Scanned       int            `json:"scanned"`
+	Reencrypted   int            `json:"reencrypted"`
+	Failed        int            `json:"failed"`
+	PendingTokens map[string]int `json:"pending_tokens,omitempty"`
+	Error         string         `json:"error,omitempty"`
+}
+
+// KeyringStatusResponse represents the response payload describing the keyring and the rewrap job.
+// @Description Keyring status format
+type KeyringStatusResponse struct {
+	ActiveVersion int                  `json:"active_version"`
+	Keys          []KeyringKeyResponse `json:"keys"`
+	Rewrap        RewrapStatusResponse `json:"rewrap"`
+}
+
+// SealStatusResponse represents the response payload describing whether the server is sealed and, while unsealed,
+// which root key is active.
+// @Description Seal status format
+// @Example { "sealed": false, "type": "key_file", "progress": 0, "key_version": 2, "key_fingerprint": "9f86d081884c7d65" }
+type SealStatusResponse struct {
+	Sealed         bool   `json:"sealed"`
+	Type           string `json:"type"`
+	Shares         int    `json:"shares,omitempty"`
+	Threshold      int    `json:"threshold,omitempty"`
+	Progress       int    `json:"progress"`
+	KeyVersion     int    `json:"key_version,omitempty"`
+	KeyFingerprint string `json:"key_fingerprint,omitempty"`
+}
+
+// TransitKeyVersionResponse represents a single version of a transit key, without its key material.
+// @Description Transit key version format
+type TransitKeyVersionResponse struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransitKeyResponse represents the response payload describing a transit key.
+// @Description Transit key format
+type TransitKeyResponse struct {
+	Name                 string                      `json:"name"`
+	LatestVersion        int                         `json:"latest_version"`
+	MinDecryptionVersion int                         `json:"min_decryption_version"`
+	Versions             []TransitKeyVersionResponse `json:"versions"`
+	CreatedAt            time.Time                   `json:"created_at"`
+}
+
+// TransitCiphertextResponse represents the response payload containing data encrypted with a transit key.
+// @Description Transit ciphertext response format
+// @Example { "ciphertext": "transit:v1:q2LlJ7Tz..." }
+type TransitCiphertextResponse struct {
+	Ciphertext string `json:"ciphertext"`
+}
+
+// TransitPlaintextResponse represents the response payload containing data decrypted with a transit key.
+// @Description Transit plaintext response format
+// @Example { "plaintext": "aGVsbG8gd29ybGQ=" }
+type TransitPlaintextResponse struct {
+	Plaintext string `json:"plaintext"` // Base64 encoded data
+}
+
+// TransitDataKeyResponse represents the response payload containing a generated data key.
+// The plaintext key is only included when requested.
+// @Description Transit data key response format
+// @Example { "ciphertext": "transit:v1:q2LlJ7Tz...", "plaintext": "3q2+7w==..." }
+type TransitDataKeyResponse struct {
+	Ciphertext string `json:"ciphertext"`
+	Plaintext  string `json:"plaintext,omitempty"` // Base64 encoded data key
+}
+
+// SigningKeyResponse represents the response payload describing a signing key and its public key.
+// @Description Signing key format
+type SigningKeyResponse struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	PublicKey string    `json:"public_key"` // PEM encoded PKIX public key
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SignResponse represents the response payload containing a signature.
+// @Description Sign response format
+// @Example { "signature": "MEUCIQD..." }
+type SignResponse struct {
+	Signature string `json:"signature"` // Base64 encoded signature
+}
+
+// VerifyResponse represents the response payload of a signature verification.
+// @Description Verify response format
+// @Example { "valid": true }
+type VerifyResponse struct {
 	Valid bool `json:"valid"`
 }
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/admin"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+	"go-secrets/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminRoutes defines the routes for inspecting the server under the `/admin` endpoint, which require the root token.
+func AdminRoutes(router *gin.Engine, logger internal.LoggerService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, stats internal.StatsService, config models.ServerConfigResponse) {
+	// Initialize the AdminController
+	controller := &controllers.AdminControllerImpl{
+		Logger:       logger,
+		Seal:         seal,
+		StoreStats:   stats,
+		ServerConfig: config,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	adminGroup := router.Group("/admin").Use(sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware())
+	{
+		adminGroup.GET("/stats", controller.Stats)
+		adminGroup.GET("/config", controller.Config)
+	}
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/approle"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppRoleRoutes defines the routes for managing AppRoles under the `/sys/approle` endpoint, and for logging in with
+// them under the `/auth/approle` endpoint.
+func AppRoleRoutes(router *gin.Engine, logger internal.LoggerService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, appRoles internal.AppRoleService, policies internal.PolicyService) {
+	// Initialize the AppRoleController
+	controller := &controllers.AppRoleControllerImpl{
+		Logger:   logger,
+		Redis:    redis,
+		Token:    token,
+		AppRoles: appRoles,
+		Policies: policies,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	roleGroup := router.Group("/sys/approle/roles").Use(sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware())
+	{
+		roleGroup.PUT("/:name", controller.Put)
+		roleGroup.GET("/:name", controller.Get)
+		roleGroup.DELETE("/:name", controller.Delete)
+		roleGroup.POST("/:name/secret-id", controller.IssueSecretID)
+	}
+
+	loginGroup := router.Group("/auth/approle", sealMiddleware.SealMiddleware())
+	{
+		loginGroup.POST("/login", controller.Login)
+	}
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/cert"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CertRoutes defines the routes for managing certificate roles under the `/sys/cert` endpoint, and for logging in
+// with client certificates under the `/auth/cert` endpoint.
+func CertRoutes(router *gin.Engine, logger internal.LoggerService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, certAuth internal.CertAuthService, policies internal.PolicyService) {
+	// Initialize the CertController
+	controller := &controllers.CertControllerImpl{
+		Logger:   logger,
+		Redis:    redis,
+		Token:    token,
+		CertAuth: certAuth,
+		Policies: policies,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	configGroup := router.Group("/sys/cert").Use(sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware())
+	{
+		configGroup.PUT("/roles/:name", controller.PutRole)
+		configGroup.GET("/roles/:name", controller.GetRole)
+		configGroup.DELETE("/roles/:name", controller.DeleteRole)
+	}
+
+	loginGroup := router.Group("/auth/cert", sealMiddleware.SealMiddleware())
+	{
+		loginGroup.POST("/login", controller.Login)
+	}
+}
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/jwt"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWTRoutes defines the routes for managing JWT issuers and roles under the `/sys/jwt` endpoint, and for logging in
+// with JWTs under the `/auth/jwt` endpoint.
+func JWTRoutes(router *gin.Engine, logger internal.LoggerService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, jwtAuth internal.JWTAuthService, policies internal.PolicyService) {
+	// Initialize the JWTController
+	controller := &controllers.JWTControllerImpl{
+		Logger:   logger,
+		Redis:    redis,
+		Token:    token,
+		JWTAuth:  jwtAuth,
+		Policies: policies,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	configGroup := router.Group("/sys/jwt").Use(sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware())
+	{
+		configGroup.PUT("/issuers/:name", controller.PutIssuer)
+		configGroup.GET("/issuers/:name", controller.GetIssuer)
+		configGroup.DELETE("/issuers/:name", controller.DeleteIssuer)
+		configGroup.PUT("/roles/:name", controller.PutRole)
+		configGroup.GET("/roles/:name", controller.GetRole)
+		configGroup.DELETE("/roles/:name", controller.DeleteRole)
+	}
+
+	loginGroup := router.Group("/auth/jwt", sealMiddleware.SealMiddleware())
+	{
+		loginGroup.POST("/login", controller.Login)
+	}
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/keyring"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KeyringRoutes defines the routes for managing the root keys under the `/sys/keyring` endpoint.
+func KeyringRoutes(router *gin.Engine, logger internal.LoggerService, keyring internal.KeyringService, rewrap internal.RewrapService, redis internal.RedisService, token internal.TokenService, seal internal.SealService) {
+	// Initialize the KeyringController
+	controller := &controllers.KeyringControllerImpl{
+		Logger:  logger,
+		Keyring: keyring,
+		Rewrap:  rewrap,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	keyringGroup := router.Group("/sys/keyring").Use(sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware())
+	{
+		keyringGroup.GET("", controller.Status)
+		keyringGroup.POST("/rotate", controller.Rotate)
+		keyringGroup.POST("/rewrap", controller.StartRewrap)
+		keyringGroup.DELETE("/:version", controller.Retire)
+	}
+}
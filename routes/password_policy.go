@@ -0,0 +1,36 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/passwordpolicy"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordPolicyRoutes defines the routes for managing named password policies under the `/sys/password-policies` endpoint.
+func PasswordPolicyRoutes(router *gin.Engine, logger internal.LoggerService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, passwordPolicies internal.PasswordPolicyService) {
+	// Initialize the PasswordPolicyController
+	controller := &controllers.PasswordPolicyControllerImpl{
+		Logger:           logger,
+		PasswordPolicies: passwordPolicies,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	policyGroup := router.Group("/sys/password-policies").Use(sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware())
+	{
+		policyGroup.PUT("/:name", controller.Put)
+		policyGroup.GET("/:name", controller.Get)
+		policyGroup.DELETE("/:name", controller.Delete)
+	}
+}
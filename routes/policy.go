@@ -0,0 +1,36 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/policy"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PolicyRoutes defines the routes for managing named token policies under the `/sys/policies` endpoint.
+func PolicyRoutes(router *gin.Engine, logger internal.LoggerService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, policies internal.PolicyService) {
+	// Initialize the PolicyController
+	controller := &controllers.PolicyControllerImpl{
+		Logger:   logger,
+		Policies: policies,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	policyGroup := router.Group("/sys/policies").Use(sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware())
+	{
+		policyGroup.PUT("/:name", controller.Put)
+		policyGroup.GET("/:name", controller.Get)
+		policyGroup.DELETE("/:name", controller.Delete)
+	}
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/seal"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SealRoutes defines the routes for sealing and unsealing the server under the `/sys` endpoint.
+func SealRoutes(router *gin.Engine, logger internal.LoggerService, redis internal.RedisService, token internal.TokenService, seal internal.SealService) {
+	// Initialize the SealController
+	controller := &controllers.SealControllerImpl{
+		Logger: logger,
+		Seal:   seal,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	sysGroup := router.Group("/sys")
+	{
+		sysGroup.GET("/seal-status", controller.Status)
+		sysGroup.POST("/unseal", controller.Unseal)
+		sysGroup.POST("/seal", sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware(), controller.SealServer)
+	}
+}
@@ -9,23 +9,33 @@ import (
 )
 
 // SecretRoutes defines the routes for managing secrets under the `/secret` endpoint.
-func SecretRoutes(router *gin.Engine, logger internal.LoggerService, crypto internal.CryptoService, redis internal.RedisService, token internal.TokenService) {
+func SecretRoutes(router *gin.Engine, logger internal.LoggerService, crypto internal.CryptoService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, passwordPolicies internal.PasswordPolicyService, blobs internal.BlobService, maxValueSize int64, policies internal.PolicyService, certs internal.CertAuthService) {
 	// Initialize the SecretsController
 	controller := &controllers.SecretsControllerImpl{
-		Logger: logger,
-		Crypto: crypto,
-		Redis:  redis,
-		Token:  token,
+		Logger:           logger,
+		Crypto:           crypto,
+		Redis:            redis,
+		Token:            token,
+		PasswordPolicies: passwordPolicies,
+		Blobs:            blobs,
+		MaxValueSize:     maxValueSize,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
 	}
 
 	// Initialize AuthMiddlewareImpl
 	authMiddleware := &middlewares.AuthMiddlewareImpl{
-		Crypto: crypto,
-		Token:  token,
-		Redis:  redis,
+		Crypto:   crypto,
+		Token:    token,
+		Redis:    redis,
+		Policies: policies,
+		Certs:    certs,
 	}
 
-	secretGroup := router.Group("/secret").Use(authMiddleware.AuthMiddleware())
+	secretGroup := router.Group("/secret").Use(sealMiddleware.SealMiddleware(), authMiddleware.AuthMiddleware())
 	{
 		secretGroup.POST("/*key", controller.Set)
 		secretGroup.GET("/*key", controller.Get)
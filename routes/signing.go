@@ -0,0 +1,58 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/signing"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SigningRoutes defines the routes for signing data with server managed keys under the `/signing` endpoint.
+// Creating keys requires the root token, using them requires a valid token.
+func SigningRoutes(router *gin.Engine, logger internal.LoggerService, crypto internal.CryptoService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, signing internal.SigningService, policies internal.PolicyService, certs internal.CertAuthService) {
+	// Initialize the SigningController
+	controller := &controllers.SigningControllerImpl{
+		Logger:  logger,
+		Signing: signing,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AuthMiddlewareImpl
+	authMiddleware := &middlewares.AuthMiddlewareImpl{
+		Crypto:   crypto,
+		Token:    token,
+		Redis:    redis,
+		Policies: policies,
+		Certs:    certs,
+	}
+
+	// Signing keys are shared by all tokens, so signing with one needs a policy granting it. Verifying and exporting
+	// public keys only uses public key material.
+	signMiddleware := &middlewares.AuthMiddlewareImpl{
+		Crypto:        crypto,
+		Token:         token,
+		Redis:         redis,
+		Policies:      policies,
+		Certs:         certs,
+		RequirePolicy: true,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	signingGroup := router.Group("/signing").Use(sealMiddleware.SealMiddleware())
+	{
+		signingGroup.POST("/keys/:name", adminMiddleware.AdminMiddleware(), controller.CreateKey)
+		signingGroup.GET("/keys/:name", authMiddleware.AuthMiddleware(), controller.GetKey)
+		signingGroup.POST("/sign/:name", signMiddleware.AuthMiddleware(), controller.Sign)
+		signingGroup.POST("/verify/:name", authMiddleware.AuthMiddleware(), controller.Verify)
+	}
+}
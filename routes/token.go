@@ -8,27 +8,52 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// TokenRoute defines the routes for managing tokens under the `/token` endpoint.
-func TokenRoute(router *gin.Engine, logger internal.LoggerService, crypto internal.CryptoService, redis internal.RedisService, token internal.TokenService) {
+// TokenRoute defines the routes for managing tokens under the `/token` endpoint, and for managing all tokens by
+// accessor under the `/sys/tokens` endpoint.
+func TokenRoute(router *gin.Engine, logger internal.LoggerService, crypto internal.CryptoService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, policies internal.PolicyService, anonymousTokens bool, certs internal.CertAuthService) {
 	// Initialize the TokenController
 	controller := &controllers.TokenControllerImpl{
-		Logger: logger,
-		Crypto: crypto,
-		Redis:  redis,
-		Token:  token,
+		Logger:          logger,
+		Crypto:          crypto,
+		Redis:           redis,
+		Token:           token,
+		Policies:        policies,
+		AnonymousTokens: anonymousTokens,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
 	}
 
-	// Initialize AuthMiddlewareImpl
+	// Initialize AuthMiddlewareImpl without policies, a token may always look up, validate, renew and revoke itself
 	authMiddleware := &middlewares.AuthMiddlewareImpl{
 		Crypto: crypto,
 		Token:  token,
 		Redis:  redis,
+		Certs:  certs,
 	}
 
-	tokenGroup := router.Group("/token")
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	tokenGroup := router.Group("/token", sealMiddleware.SealMiddleware())
 	{
 		tokenGroup.GET("", controller.Generate)
+		tokenGroup.POST("/child", authMiddleware.AuthMiddleware(), controller.CreateChild)
+		tokenGroup.POST("/renew", authMiddleware.AuthMiddleware(), controller.Renew)
 		tokenGroup.GET("/valid", authMiddleware.AuthMiddleware(), controller.Validate)
+		tokenGroup.GET("/self", authMiddleware.AuthMiddleware(), controller.Self)
 		tokenGroup.DELETE("", authMiddleware.AuthMiddleware(), controller.Delete)
 	}
+
+	accessorGroup := router.Group("/sys/tokens").Use(sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware())
+	{
+		accessorGroup.GET("", controller.ListAccessors)
+		accessorGroup.GET("/:accessor", controller.LookupAccessor)
+		accessorGroup.DELETE("/:accessor", controller.RevokeAccessor)
+	}
 }
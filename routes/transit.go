@@ -0,0 +1,57 @@
+package routes
+
+import (
+	controllers "go-secrets/controllers/transit"
+	"go-secrets/internal"
+	"go-secrets/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransitRoutes defines the routes for encrypting data with server managed keys under the `/transit` endpoint.
+// Managing keys requires the root token, using them requires a valid token.
+func TransitRoutes(router *gin.Engine, logger internal.LoggerService, crypto internal.CryptoService, redis internal.RedisService, token internal.TokenService, seal internal.SealService, transit internal.TransitService, policies internal.PolicyService, certs internal.CertAuthService) {
+	// Initialize the TransitController
+	controller := &controllers.TransitControllerImpl{
+		Logger:  logger,
+		Transit: transit,
+	}
+
+	// Initialize SealMiddlewareImpl
+	sealMiddleware := &middlewares.SealMiddlewareImpl{
+		Seal: seal,
+	}
+
+	// Initialize AuthMiddlewareImpl
+	authMiddleware := &middlewares.AuthMiddlewareImpl{
+		Crypto:   crypto,
+		Token:    token,
+		Redis:    redis,
+		Policies: policies,
+		Certs:    certs,
+		// Transit keys are shared by all tokens, so using one needs a policy granting it
+		RequirePolicy: true,
+	}
+
+	// Initialize AdminMiddlewareImpl
+	adminMiddleware := &middlewares.AdminMiddlewareImpl{
+		Token: token,
+		Redis: redis,
+	}
+
+	keysGroup := router.Group("/transit/keys").Use(sealMiddleware.SealMiddleware(), adminMiddleware.AdminMiddleware())
+	{
+		keysGroup.POST("/:name", controller.CreateKey)
+		keysGroup.GET("/:name", controller.GetKey)
+		keysGroup.POST("/:name/rotate", controller.RotateKey)
+		keysGroup.POST("/:name/config", controller.ConfigureKey)
+	}
+
+	transitGroup := router.Group("/transit").Use(sealMiddleware.SealMiddleware(), authMiddleware.AuthMiddleware())
+	{
+		transitGroup.POST("/encrypt/:name", controller.Encrypt)
+		transitGroup.POST("/decrypt/:name", controller.Decrypt)
+		transitGroup.POST("/rewrap/:name", controller.Rewrap)
+		transitGroup.POST("/datakey/:type/:name", controller.DataKey)
+	}
+}